@@ -0,0 +1,91 @@
+// Package fpath implements a micro language for querying data.
+package fpath
+
+import (
+	"fmt"
+
+	"github.com/fcutting/fpath/internal/evaluator"
+	"github.com/fcutting/fpath/internal/lexer"
+	"github.com/fcutting/fpath/internal/parser"
+)
+
+// DefaultDivisionPrecision is the number of decimal places kept when a
+// division doesn't have an exact result, unless a Query is configured
+// otherwise.
+const DefaultDivisionPrecision = evaluator.DefaultDivisionPrecision
+
+// DivisionByZeroError is returned by Evaluate when a query divides by zero.
+type DivisionByZeroError = evaluator.DivisionByZeroError
+
+// Query is a compiled fpath query that can be evaluated against any number of
+// inputs.
+//
+// DivisionPrecision is the number of decimal places kept when a division
+// doesn't have an exact result. Compile sets it to DefaultDivisionPrecision.
+type Query struct {
+	DivisionPrecision int32
+
+	query      string
+	expression parser.Expression
+}
+
+// Compile parses a query and returns a Query that can be evaluated.
+// If the query is not valid fpath syntax, Compile returns an error.
+func Compile(query string) (*Query, error) {
+	p := parser.NewParser(lexer.NewLexer(query))
+	expression, err := p.ParseQuery()
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse query: %w", err)
+	}
+
+	return &Query{
+		DivisionPrecision: DefaultDivisionPrecision,
+		query:             query,
+		expression:        expression,
+	}, nil
+}
+
+// MustCompile is like Compile but panics if the query cannot be compiled.
+func MustCompile(query string) *Query {
+	q, err := Compile(query)
+
+	if err != nil {
+		panic(fmt.Sprintf("fpath: Compile(%q): %s", query, err))
+	}
+
+	return q
+}
+
+// String returns the source text of the query.
+func (q *Query) String() string {
+	return q.query
+}
+
+// Evaluate runs the query against the provided data and returns the result.
+// The data is typically the result of decoding JSON into an any value, though
+// any combination of Go numbers, strings, bools, slices and maps with string
+// keys is accepted. Floats that are NaN or infinite are rejected, since they
+// have no decimal value.
+// Results are returned as Go values: nil for null, bool for booleans,
+// decimal.Decimal for numbers, string for strings, []any for lists and
+// map[string]any for objects.
+func (q *Query) Evaluate(data any) (result any, err error) {
+	input, err := evaluator.NewValue(data)
+
+	if err != nil {
+		err = fmt.Errorf("failed to convert data: %w", err)
+		return
+	}
+
+	e := evaluator.NewEvaluator()
+	e.DivisionPrecision = q.DivisionPrecision
+	value, err := e.EvaluateExpression(q.expression, input)
+
+	if err != nil {
+		err = fmt.Errorf("failed to evaluate query: %w", err)
+		return
+	}
+
+	return value.Native(), nil
+}
@@ -0,0 +1,183 @@
+package fpath
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/gkampitakis/go-snaps/snaps"
+)
+
+func TestMain(m *testing.M) {
+	r := m.Run()
+	snaps.Clean(m, snaps.CleanOpts{Sort: true})
+	os.Exit(r)
+}
+
+func Test_Query_Evaluate(t *testing.T) {
+	testCases := map[string]struct {
+		query string
+		data  any
+	}{
+		"Number": {
+			query: "2",
+			data:  0,
+		},
+		"Equals": {
+			query: "2 equals 4",
+			data:  0,
+		},
+		"Nil data": {
+			query: "2 equals 2",
+			data:  nil,
+		},
+		"Path": {
+			query: "order.total equals 100",
+			data: map[string]any{
+				"order": map[string]any{"total": 100},
+			},
+		},
+		"Wildcard": {
+			query: "items[*].id",
+			data: map[string]any{
+				"items": []any{map[string]any{"id": 1}, map[string]any{"id": 2}},
+			},
+		},
+		"Quoted label": {
+			query: `."content-type" contains "json"`,
+			data:  map[string]any{"content-type": "application/json"},
+		},
+		"Null": {
+			query: "deleted_at equals null and active equals true",
+			data:  map[string]any{"active": true},
+		},
+		"Object literal": {
+			query: `{"ids": items[*].id, "count": 2}`,
+			data: map[string]any{
+				"items": []any{map[string]any{"id": 1}, map[string]any{"id": 2}},
+			},
+		},
+		"Filter scalars": {
+			query: `tags[. startswith "b"]`,
+			data:  map[string]any{"tags": []any{"alpha", "beta", "bravo"}},
+		},
+		"Extract": {
+			query: "line extract `(?P<method>[A-Z]+) (?P<path>\\S+)`",
+			data:  map[string]any{"line": "GET /index.html HTTP/1.1"},
+		},
+		"Extract raw string": {
+			query: "message extract `user=(\\w+)` equals \"bob\"",
+			data:  map[string]any{"message": "login user=bob ok"},
+		},
+		"Call": {
+			query: `join(split(lower(path), "/"), ".")`,
+			data:  map[string]any{"path": "API/V1/Users"},
+		},
+		"Call wildcard arithmetic": {
+			query: "sum(items[*].price * 2)",
+			data: map[string]any{
+				"items": []any{map[string]any{"price": 1.5}, map[string]any{"price": 2}},
+			},
+		},
+		"Arithmetic": {
+			query: "(total - discount) / 2",
+			data:  map[string]any{"total": 100, "discount": 20},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			query, err := Compile(tc.query)
+
+			if err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+
+			result, err := query.Evaluate(tc.data)
+
+			if err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+
+			snaps.MatchSnapshot(t, fmt.Sprintf("%T %v", result, result))
+		})
+	}
+}
+
+func Test_Compile_Error(t *testing.T) {
+	testCases := map[string]struct {
+		query string
+	}{
+		"Empty": {
+			query: "",
+		},
+		"Invalid rune": {
+			query: "2 equals $",
+		},
+		"Unsupported operation": {
+			query: "2 4",
+		},
+		"Unknown function": {
+			query: "shout(name)",
+		},
+		"Function argument condition": {
+			query: "length(a equals 1)",
+		},
+		"Function arity": {
+			query: "round()",
+		},
+		"Invalid pattern": {
+			query: "path matches `(`",
+		},
+		"Double quoted pattern escape": {
+			query: `message extract "user=(\w+)" equals "bob"`,
+		},
+		"Exponent too large": {
+			query: "1e999999999 equals 1",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			_, err := Compile(tc.query)
+
+			if err == nil {
+				t.Fatalf("Expected error but none returned")
+			}
+
+			snaps.MatchSnapshot(t, err.Error())
+		})
+	}
+}
+
+func Test_Query_Evaluate_DivisionPrecision(t *testing.T) {
+	query := MustCompile("2 / 3")
+	query.DivisionPrecision = 4
+	result, err := query.Evaluate(nil)
+
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	snaps.MatchSnapshot(t, fmt.Sprintf("%v", result))
+}
+
+func Test_Query_Evaluate_DivisionByZero(t *testing.T) {
+	query := MustCompile("total / count")
+	_, err := query.Evaluate(map[string]any{"total": 10, "count": 0})
+
+	if !errors.As(err, &DivisionByZeroError{}) {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+}
+
+func Test_MustCompile_Panic(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatalf("Expected panic but none occurred")
+		}
+	}()
+
+	MustCompile("equals")
+}
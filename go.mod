@@ -2,7 +2,10 @@ module github.com/fcutting/fpath
 
 go 1.22.1
 
-require github.com/gkampitakis/go-snaps v0.5.4
+require (
+	github.com/gkampitakis/go-snaps v0.5.4
+	github.com/shopspring/decimal v1.4.0
+)
 
 require (
 	github.com/gkampitakis/ciinfo v0.3.0 // indirect
@@ -11,7 +14,6 @@ require (
 	github.com/kr/text v0.2.0 // indirect
 	github.com/maruel/natural v1.1.1 // indirect
 	github.com/rogpeppe/go-internal v1.12.0 // indirect
-	github.com/shopspring/decimal v1.4.0 // indirect
 	github.com/tidwall/gjson v1.17.0 // indirect
 	github.com/tidwall/match v1.1.1 // indirect
 	github.com/tidwall/pretty v1.2.1 // indirect
@@ -0,0 +1,167 @@
+package evaluator
+
+import (
+	"fmt"
+
+	"github.com/fcutting/fpath/internal/parser"
+	"github.com/shopspring/decimal"
+)
+
+// DefaultDivisionPrecision is the number of decimal places kept by a new
+// Evaluator when a division doesn't have an exact result.
+const DefaultDivisionPrecision = 16
+
+// DivisionByZeroError is returned when a number is divided by zero or the
+// remainder of dividing a number by zero is requested.
+type DivisionByZeroError struct{}
+
+// Error returns a description of the division by zero.
+func (DivisionByZeroError) Error() string {
+	return "division by zero"
+}
+
+// EvaluateAdd returns the sum of the values of the add node's expressions.
+func (e *Evaluator) EvaluateAdd(add parser.AddNode, input Value) (result Value, err error) {
+	return e.arithmetic(add.Left, add.Right, input, func(a, b decimal.Decimal) (decimal.Decimal, error) {
+		return a.Add(b), nil
+	})
+}
+
+// EvaluateSubtract returns the difference of the values of the subtract node's
+// expressions.
+func (e *Evaluator) EvaluateSubtract(subtract parser.SubtractNode, input Value) (result Value, err error) {
+	return e.arithmetic(subtract.Left, subtract.Right, input, func(a, b decimal.Decimal) (decimal.Decimal, error) {
+		return a.Sub(b), nil
+	})
+}
+
+// EvaluateMultiply returns the product of the values of the multiply node's
+// expressions.
+func (e *Evaluator) EvaluateMultiply(multiply parser.MultiplyNode, input Value) (result Value, err error) {
+	return e.arithmetic(multiply.Left, multiply.Right, input, func(a, b decimal.Decimal) (decimal.Decimal, error) {
+		return a.Mul(b), nil
+	})
+}
+
+// EvaluateDivide returns the quotient of the values of the divide node's
+// expressions, rounded to the evaluator's division precision.
+// Dividing by zero returns a DivisionByZeroError.
+func (e *Evaluator) EvaluateDivide(divide parser.DivideNode, input Value) (result Value, err error) {
+	return e.arithmetic(divide.Left, divide.Right, input, func(a, b decimal.Decimal) (decimal.Decimal, error) {
+		if b.IsZero() {
+			return decimal.Decimal{}, DivisionByZeroError{}
+		}
+
+		return a.DivRound(b, e.DivisionPrecision), nil
+	})
+}
+
+// EvaluateModulo returns the remainder of dividing the values of the modulo
+// node's expressions, which has the same sign as the dividend.
+// Dividing by zero returns a DivisionByZeroError.
+func (e *Evaluator) EvaluateModulo(modulo parser.ModuloNode, input Value) (result Value, err error) {
+	return e.arithmetic(modulo.Left, modulo.Right, input, func(a, b decimal.Decimal) (decimal.Decimal, error) {
+		if b.IsZero() {
+			return decimal.Decimal{}, DivisionByZeroError{}
+		}
+
+		return a.Mod(b), nil
+	})
+}
+
+// EvaluateNegate returns the negation of the value of the negate node's
+// expression.
+func (e *Evaluator) EvaluateNegate(negate parser.NegateNode, input Value) (result Value, err error) {
+	value, err := e.EvaluateExpression(negate.Expression, input)
+
+	if err != nil {
+		err = fmt.Errorf("failed to evaluate expression: %w", err)
+		return
+	}
+
+	return applyArithmetic(NumberValue{Value: decimal.Zero}, value, func(a, b decimal.Decimal) (decimal.Decimal, error) {
+		return a.Sub(b), nil
+	})
+}
+
+// arithmetic returns the result of applying an arithmetic function to the
+// values of the left and right expressions.
+func (e *Evaluator) arithmetic(left, right parser.Expression, input Value, apply func(a, b decimal.Decimal) (decimal.Decimal, error)) (result Value, err error) {
+	a, err := e.EvaluateExpression(left, input)
+
+	if err != nil {
+		err = fmt.Errorf("failed to evaluate left side: %w", err)
+		return
+	}
+
+	b, err := e.EvaluateExpression(right, input)
+
+	if err != nil {
+		err = fmt.Errorf("failed to evaluate right side: %w", err)
+		return
+	}
+
+	return applyArithmetic(a, b, apply)
+}
+
+// applyArithmetic returns the result of applying an arithmetic function to two
+// values.
+// Arithmetic involving null results in null, so missing fields don't cause
+// errors. Values other than numbers return an UnsupportedTypeError.
+// If either value is a MultiValue, the function is applied to every pairing of
+// their values and the results are collected into a new MultiValue.
+func applyArithmetic(a, b Value, apply func(a, b decimal.Decimal) (decimal.Decimal, error)) (result Value, err error) {
+	_, leftMulti := a.(MultiValue)
+	_, rightMulti := b.(MultiValue)
+
+	if !leftMulti && !rightMulti {
+		return applyNumbers(a, b, apply)
+	}
+
+	results := []Value{}
+
+	for _, x := range values(a) {
+		for _, y := range values(b) {
+			var value Value
+			value, err = applyNumbers(x, y, apply)
+
+			if err != nil {
+				return
+			}
+
+			results = append(results, value)
+		}
+	}
+
+	return MultiValue{Value: results}, nil
+}
+
+// applyNumbers returns the result of applying an arithmetic function to two
+// number values.
+func applyNumbers(a, b Value, apply func(a, b decimal.Decimal) (decimal.Decimal, error)) (result Value, err error) {
+	if a.Type() == ValueType_Null || b.Type() == ValueType_Null {
+		return NullValue{}, nil
+	}
+
+	x, ok := a.(NumberValue)
+
+	if !ok {
+		err = UnsupportedTypeError{Type: a.Type()}
+		return
+	}
+
+	y, ok := b.(NumberValue)
+
+	if !ok {
+		err = UnsupportedTypeError{Type: b.Type()}
+		return
+	}
+
+	value, err := apply(x.Value, y.Value)
+
+	if err != nil {
+		return
+	}
+
+	return NumberValue{Value: value}, nil
+}
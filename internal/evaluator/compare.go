@@ -0,0 +1,144 @@
+package evaluator
+
+import (
+	"fmt"
+	"strings"
+)
+
+// TypeMismatchError is returned when two values can't be compared because
+// they are of different types.
+type TypeMismatchError struct {
+	Left  int
+	Right int
+}
+
+// Error returns a description of the mismatched types.
+func (e TypeMismatchError) Error() string {
+	return fmt.Sprintf("mismatched types: %s and %s", ValueTypeString[e.Left], ValueTypeString[e.Right])
+}
+
+// UnsupportedTypeError is returned when a value's type doesn't support the
+// requested operation.
+type UnsupportedTypeError struct {
+	Type int
+}
+
+// Error returns a description of the unsupported type.
+func (e UnsupportedTypeError) Error() string {
+	return fmt.Sprintf("unsupported type: %s", ValueTypeString[e.Type])
+}
+
+// Equal returns whether two values are equal.
+// Values of different types are never equal. Numbers are equal when they
+// represent the same decimal value regardless of precision, lists are equal
+// when their elements are equal in order, and objects are equal when they have
+// the same keys with equal values.
+func Equal(a, b Value) bool {
+	if a.Type() != b.Type() {
+		return false
+	}
+
+	switch a := a.(type) {
+	case NullValue:
+		return true
+	case BooleanValue:
+		return a.Value == b.(BooleanValue).Value
+	case NumberValue:
+		return a.Value.Equal(b.(NumberValue).Value)
+	case StringValue:
+		return a.Value == b.(StringValue).Value
+	case ListValue:
+		b := b.(ListValue)
+
+		if len(a.Value) != len(b.Value) {
+			return false
+		}
+
+		for i := range a.Value {
+			if !Equal(a.Value[i], b.Value[i]) {
+				return false
+			}
+		}
+
+		return true
+	case ObjectValue:
+		b := b.(ObjectValue)
+
+		if len(a.Value) != len(b.Value) {
+			return false
+		}
+
+		for k, av := range a.Value {
+			bv, ok := b.Value[k]
+
+			if !ok || !Equal(av, bv) {
+				return false
+			}
+		}
+
+		return true
+	default:
+		return false
+	}
+}
+
+// Compare returns -1, 0 or 1 depending on whether a is less than, equal to or
+// greater than b.
+// Only values of the same type can be ordered. Booleans order false before
+// true, numbers order by value, strings order lexicographically by byte and
+// lists order by their first unequal element, then by length.
+// If the values are of different types, Compare returns a TypeMismatchError.
+// If the values are objects, Compare returns an UnsupportedTypeError.
+func Compare(a, b Value) (result int, err error) {
+	if a.Type() != b.Type() {
+		err = TypeMismatchError{Left: a.Type(), Right: b.Type()}
+		return
+	}
+
+	switch a := a.(type) {
+	case NullValue:
+		return 0, nil
+	case BooleanValue:
+		b := b.(BooleanValue)
+
+		switch {
+		case a.Value == b.Value:
+			return 0, nil
+		case b.Value:
+			return -1, nil
+		default:
+			return 1, nil
+		}
+	case NumberValue:
+		return a.Value.Cmp(b.(NumberValue).Value), nil
+	case StringValue:
+		return strings.Compare(a.Value, b.(StringValue).Value), nil
+	case ListValue:
+		b := b.(ListValue)
+
+		for i := 0; i < len(a.Value) && i < len(b.Value); i++ {
+			result, err = Compare(a.Value[i], b.Value[i])
+
+			if err != nil {
+				err = fmt.Errorf("failed to compare element %d: %w", i, err)
+				return
+			}
+
+			if result != 0 {
+				return result, nil
+			}
+		}
+
+		switch {
+		case len(a.Value) < len(b.Value):
+			return -1, nil
+		case len(a.Value) > len(b.Value):
+			return 1, nil
+		default:
+			return 0, nil
+		}
+	default:
+		err = UnsupportedTypeError{Type: a.Type()}
+		return
+	}
+}
@@ -0,0 +1,182 @@
+package evaluator
+
+import (
+	"testing"
+
+	"github.com/gkampitakis/go-snaps/snaps"
+)
+
+func _mustNewValue(t *testing.T, data any) Value {
+	t.Helper()
+	value, err := NewValue(data)
+
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	return value
+}
+
+func Test_Equal(t *testing.T) {
+	testCases := map[string]struct {
+		a        any
+		b        any
+		expected bool
+	}{
+		"Null": {
+			a:        nil,
+			b:        nil,
+			expected: true,
+		},
+		"Boolean": {
+			a:        true,
+			b:        true,
+			expected: true,
+		},
+		"Number precision": {
+			a:        2,
+			b:        2.0,
+			expected: true,
+		},
+		"Number unequal": {
+			a:        2,
+			b:        4,
+			expected: false,
+		},
+		"String": {
+			a:        "a",
+			b:        "a",
+			expected: true,
+		},
+		"List": {
+			a:        []any{1, "a"},
+			b:        []any{1, "a"},
+			expected: true,
+		},
+		"List order": {
+			a:        []any{1, "a"},
+			b:        []any{"a", 1},
+			expected: false,
+		},
+		"List length": {
+			a:        []any{1},
+			b:        []any{1, 1},
+			expected: false,
+		},
+		"Object": {
+			a:        map[string]any{"a": 1, "b": []any{2}},
+			b:        map[string]any{"b": []any{2}, "a": 1},
+			expected: true,
+		},
+		"Object keys": {
+			a:        map[string]any{"a": 1},
+			b:        map[string]any{"b": 1},
+			expected: false,
+		},
+		"Mismatched types": {
+			a:        "1",
+			b:        1,
+			expected: false,
+		},
+		"Null and false": {
+			a:        nil,
+			b:        false,
+			expected: false,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			result := Equal(_mustNewValue(t, tc.a), _mustNewValue(t, tc.b))
+
+			if result != tc.expected {
+				t.Fatalf("Unexpected result\nExpected: %v\nActual: %v", tc.expected, result)
+			}
+		})
+	}
+}
+
+func Test_Compare(t *testing.T) {
+	testCases := map[string]struct {
+		a        any
+		b        any
+		expected int
+	}{
+		"Null": {
+			a:        nil,
+			b:        nil,
+			expected: 0,
+		},
+		"Boolean": {
+			a:        false,
+			b:        true,
+			expected: -1,
+		},
+		"Number": {
+			a:        10,
+			b:        9.99,
+			expected: 1,
+		},
+		"String": {
+			a:        "apple",
+			b:        "banana",
+			expected: -1,
+		},
+		"List": {
+			a:        []any{1, 2},
+			b:        []any{1, 3},
+			expected: -1,
+		},
+		"List length": {
+			a:        []any{1, 2},
+			b:        []any{1},
+			expected: 1,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			result, err := Compare(_mustNewValue(t, tc.a), _mustNewValue(t, tc.b))
+
+			if err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+
+			if result != tc.expected {
+				t.Fatalf("Unexpected result\nExpected: %d\nActual: %d", tc.expected, result)
+			}
+		})
+	}
+}
+
+func Test_Compare_Error(t *testing.T) {
+	testCases := map[string]struct {
+		a any
+		b any
+	}{
+		"Mismatched types": {
+			a: 1,
+			b: "1",
+		},
+		"Object": {
+			a: map[string]any{},
+			b: map[string]any{},
+		},
+		"List elements": {
+			a: []any{1},
+			b: []any{"1"},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			_, err := Compare(_mustNewValue(t, tc.a), _mustNewValue(t, tc.b))
+
+			if err == nil {
+				t.Fatalf("Expected error but none returned")
+			}
+
+			snaps.MatchSnapshot(t, err.Error())
+		})
+	}
+}
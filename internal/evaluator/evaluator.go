@@ -0,0 +1,685 @@
+package evaluator
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/fcutting/fpath/internal/parser"
+)
+
+func NewEvaluator() *Evaluator {
+	return &Evaluator{
+		DivisionPrecision: DefaultDivisionPrecision,
+	}
+}
+
+// Evaluator executes a parsed AST against input data.
+//
+// Wildcards and recursive descents produce a MultiValue holding every value
+// they select. Operations applied to a MultiValue match if they match any of
+// its values, so "items[*].sku equals 1" is true when at least one sku is 1.
+//
+// Missing fields evaluate to null. Null equals only null, contains nothing, is
+// contained only by lists holding null, isn't ordered against anything and
+// makes the result of arithmetic null, so queries on missing fields evaluate to
+// false rather than failing.
+//
+// DivisionPrecision is the number of decimal places kept when a division
+// doesn't have an exact result.
+type Evaluator struct {
+	DivisionPrecision int32
+}
+
+// EvaluateBlock returns the result of folding each of the block's operations
+// over the value of its base expression.
+func (e *Evaluator) EvaluateBlock(block parser.BlockNode, input Value) (result Value, err error) {
+	result, err = e.EvaluateExpression(block.BaseExpression, input)
+
+	if err != nil {
+		err = fmt.Errorf("failed to evaluate expression: %w", err)
+		return
+	}
+
+	for _, operation := range block.Operations {
+		result, err = e.EvaluateOperation(operation, result, input)
+
+		if err != nil {
+			err = fmt.Errorf("failed to evaluate operation: %w", err)
+			return
+		}
+	}
+
+	return result, nil
+}
+
+// EvaluateOperation returns the result of applying the operation to the
+// current value.
+func (e *Evaluator) EvaluateOperation(operation parser.Operation, current, input Value) (result Value, err error) {
+	switch o := operation.(type) {
+	case parser.EqualsNode:
+		return e.EvaluateEquals(o, current, input)
+	case parser.ContainsNode:
+		return e.EvaluateContains(o, current, input)
+	case parser.GreaterNode:
+		return e.EvaluateGreater(o, current, input)
+	case parser.LesserNode:
+		return e.EvaluateLesser(o, current, input)
+	case parser.GreaterEqualsNode:
+		return e.EvaluateGreaterEquals(o, current, input)
+	case parser.LesserEqualsNode:
+		return e.EvaluateLesserEquals(o, current, input)
+	case parser.InNode:
+		return e.EvaluateIn(o, current, input)
+	case parser.BetweenNode:
+		return e.EvaluateBetween(o, current, input)
+	case parser.MatchesNode:
+		return e.EvaluateMatches(o, current, input)
+	case parser.StartsWithNode:
+		return e.EvaluateStartsWith(o, current, input)
+	case parser.EndsWithNode:
+		return e.EvaluateEndsWith(o, current, input)
+	case parser.LikeNode:
+		return e.EvaluateLike(o, current, input)
+	case parser.ExtractNode:
+		return e.EvaluateExtract(o, current, input)
+	case parser.NotOperationNode:
+		return e.EvaluateNotOperation(o, current, input)
+	default:
+		err = fmt.Errorf("unsupported operation type: %s", parser.NodeTypeString[operation.Type()])
+		return
+	}
+}
+
+// EvaluateEquals returns whether the current value is equal to the value of
+// the equals operation's expression.
+func (e *Evaluator) EvaluateEquals(equals parser.EqualsNode, current, input Value) (result Value, err error) {
+	value, err := e.EvaluateExpression(equals.Expression, input)
+
+	if err != nil {
+		err = fmt.Errorf("failed to evaluate expression: %w", err)
+		return
+	}
+
+	return anyPair(current, value, func(a, b Value) (bool, error) {
+		return Equal(a, b), nil
+	})
+}
+
+// EvaluateContains returns whether the current value contains the value of the
+// contains operation's expression.
+// Strings contain substrings, lists contain elements equal to the value and
+// objects contain keys. Null contains nothing, and only lists can contain null.
+func (e *Evaluator) EvaluateContains(contains parser.ContainsNode, current, input Value) (result Value, err error) {
+	value, err := e.EvaluateExpression(contains.Expression, input)
+
+	if err != nil {
+		err = fmt.Errorf("failed to evaluate expression: %w", err)
+		return
+	}
+
+	return anyPair(current, value, containsValue)
+}
+
+// containsValue returns whether a contains b.
+func containsValue(a, b Value) (bool, error) {
+	switch a := a.(type) {
+	case NullValue:
+		return false, nil
+	case StringValue:
+		if b.Type() == ValueType_Null {
+			return false, nil
+		}
+
+		s, ok := b.(StringValue)
+
+		if !ok {
+			return false, TypeMismatchError{Left: a.Type(), Right: b.Type()}
+		}
+
+		return strings.Contains(a.Value, s.Value), nil
+	case ListValue:
+		for _, element := range a.Value {
+			if Equal(element, b) {
+				return true, nil
+			}
+		}
+
+		return false, nil
+	case ObjectValue:
+		if b.Type() == ValueType_Null {
+			return false, nil
+		}
+
+		key, ok := b.(StringValue)
+
+		if !ok {
+			return false, TypeMismatchError{Left: a.Type(), Right: b.Type()}
+		}
+
+		_, ok = a.Value[key.Value]
+		return ok, nil
+	default:
+		return false, UnsupportedTypeError{Type: a.Type()}
+	}
+}
+
+// EvaluateGreater returns whether the current value is greater than the value
+// of the greater operation's expression.
+func (e *Evaluator) EvaluateGreater(greater parser.GreaterNode, current, input Value) (result Value, err error) {
+	value, err := e.EvaluateExpression(greater.Expression, input)
+
+	if err != nil {
+		err = fmt.Errorf("failed to evaluate expression: %w", err)
+		return
+	}
+
+	return anyPair(current, value, ordered(func(c int) bool { return c > 0 }))
+}
+
+// EvaluateLesser returns whether the current value is lesser than the value of
+// the lesser operation's expression.
+func (e *Evaluator) EvaluateLesser(lesser parser.LesserNode, current, input Value) (result Value, err error) {
+	value, err := e.EvaluateExpression(lesser.Expression, input)
+
+	if err != nil {
+		err = fmt.Errorf("failed to evaluate expression: %w", err)
+		return
+	}
+
+	return anyPair(current, value, ordered(func(c int) bool { return c < 0 }))
+}
+
+// EvaluateGreaterEquals returns whether the current value is greater than or
+// equal to the value of the greater or equals operation's expression.
+func (e *Evaluator) EvaluateGreaterEquals(greaterEquals parser.GreaterEqualsNode, current, input Value) (result Value, err error) {
+	value, err := e.EvaluateExpression(greaterEquals.Expression, input)
+
+	if err != nil {
+		err = fmt.Errorf("failed to evaluate expression: %w", err)
+		return
+	}
+
+	return anyPair(current, value, ordered(func(c int) bool { return c >= 0 }))
+}
+
+// EvaluateLesserEquals returns whether the current value is lesser than or
+// equal to the value of the lesser or equals operation's expression.
+func (e *Evaluator) EvaluateLesserEquals(lesserEquals parser.LesserEqualsNode, current, input Value) (result Value, err error) {
+	value, err := e.EvaluateExpression(lesserEquals.Expression, input)
+
+	if err != nil {
+		err = fmt.Errorf("failed to evaluate expression: %w", err)
+		return
+	}
+
+	return anyPair(current, value, ordered(func(c int) bool { return c <= 0 }))
+}
+
+// EvaluateIn returns whether the current value is contained by the value of the
+// in operation's expression, which is the reverse of a contains operation.
+func (e *Evaluator) EvaluateIn(in parser.InNode, current, input Value) (result Value, err error) {
+	value, err := e.EvaluateExpression(in.Expression, input)
+
+	if err != nil {
+		err = fmt.Errorf("failed to evaluate expression: %w", err)
+		return
+	}
+
+	return anyPair(current, value, func(a, b Value) (bool, error) {
+		return containsValue(b, a)
+	})
+}
+
+// EvaluateBetween returns whether the current value is greater than or equal
+// to the value of the between operation's lower bound and lesser than or equal
+// to the value of its upper bound.
+func (e *Evaluator) EvaluateBetween(between parser.BetweenNode, current, input Value) (result Value, err error) {
+	lower, err := e.EvaluateExpression(between.Lower, input)
+
+	if err != nil {
+		err = fmt.Errorf("failed to evaluate lower bound: %w", err)
+		return
+	}
+
+	upper, err := e.EvaluateExpression(between.Upper, input)
+
+	if err != nil {
+		err = fmt.Errorf("failed to evaluate upper bound: %w", err)
+		return
+	}
+
+	atLeast := ordered(func(c int) bool { return c >= 0 })
+	atMost := ordered(func(c int) bool { return c <= 0 })
+
+	return anyPair(current, lower, func(a, b Value) (bool, error) {
+		if ok, err := atLeast(a, b); !ok || err != nil {
+			return false, err
+		}
+
+		within, err := anyPair(a, upper, atMost)
+
+		if err != nil {
+			return false, err
+		}
+
+		return within.(BooleanValue).Value, nil
+	})
+}
+
+// EvaluateMatches returns whether the current value matches the matches
+// operation's regular expression.
+// The regular expression may match any part of the value unless it is
+// anchored.
+func (e *Evaluator) EvaluateMatches(matches parser.MatchesNode, current, input Value) (result Value, err error) {
+	return anyValue(current, matchString(matches.Pattern.MatchString))
+}
+
+// EvaluateStartsWith returns whether the current value starts with the value of
+// the starts with operation's expression.
+func (e *Evaluator) EvaluateStartsWith(startsWith parser.StartsWithNode, current, input Value) (result Value, err error) {
+	value, err := e.EvaluateExpression(startsWith.Expression, input)
+
+	if err != nil {
+		err = fmt.Errorf("failed to evaluate expression: %w", err)
+		return
+	}
+
+	return anyPair(current, value, stringPair(strings.HasPrefix))
+}
+
+// EvaluateEndsWith returns whether the current value ends with the value of the
+// ends with operation's expression.
+func (e *Evaluator) EvaluateEndsWith(endsWith parser.EndsWithNode, current, input Value) (result Value, err error) {
+	value, err := e.EvaluateExpression(endsWith.Expression, input)
+
+	if err != nil {
+		err = fmt.Errorf("failed to evaluate expression: %w", err)
+		return
+	}
+
+	return anyPair(current, value, stringPair(strings.HasSuffix))
+}
+
+// EvaluateLike returns whether the whole of the current value matches the like
+// operation's pattern.
+func (e *Evaluator) EvaluateLike(like parser.LikeNode, current, input Value) (result Value, err error) {
+	return anyValue(current, matchString(like.Regexp.MatchString))
+}
+
+// EvaluateExtract returns the substrings captured by the first match of the
+// extract operation's regular expression within the current value.
+// A pattern without capture groups extracts the whole match, a pattern with a
+// single capture group extracts that group, and a pattern with several capture
+// groups extracts a list of them. If the pattern has named capture groups, an
+// object of the named groups is extracted instead. Groups that don't take part
+// in the match are null.
+// If the pattern doesn't match, or the value is null, EvaluateExtract returns a
+// NullValue. Extracting from a MultiValue extracts from each of its values,
+// skipping those the pattern doesn't match.
+func (e *Evaluator) EvaluateExtract(extract parser.ExtractNode, current, input Value) (result Value, err error) {
+	pattern := extract.Pattern
+	names := pattern.SubexpNames()
+	named := slices.ContainsFunc(names, func(name string) bool { return name != "" })
+
+	return mapPath(current, func(value Value) (Value, bool, error) {
+		s, ok := value.(StringValue)
+
+		if !ok {
+			if value.Type() == ValueType_Null {
+				return nil, false, nil
+			}
+
+			return nil, false, UnsupportedTypeError{Type: value.Type()}
+		}
+
+		match := pattern.FindStringSubmatchIndex(s.Value)
+
+		if match == nil {
+			return nil, false, nil
+		}
+
+		group := func(i int) Value {
+			if match[2*i] < 0 {
+				return NullValue{}
+			}
+
+			return StringValue{Value: s.Value[match[2*i]:match[2*i+1]]}
+		}
+
+		switch {
+		case named:
+			fields := map[string]Value{}
+
+			for i, name := range names {
+				if name != "" {
+					fields[name] = group(i)
+				}
+			}
+
+			return ObjectValue{Value: fields}, true, nil
+		case len(names) == 1:
+			return group(0), true, nil
+		case len(names) == 2:
+			return group(1), true, nil
+		default:
+			groups := make([]Value, len(names)-1)
+
+			for i := range groups {
+				groups[i] = group(i + 1)
+			}
+
+			return ListValue{Value: groups}, true, nil
+		}
+	})
+}
+
+// matchString returns a predicate that checks a string value with match.
+// Null never matches, and values other than strings return an
+// UnsupportedTypeError.
+func matchString(match func(s string) bool) func(a Value) (bool, error) {
+	return func(a Value) (bool, error) {
+		switch a := a.(type) {
+		case NullValue:
+			return false, nil
+		case StringValue:
+			return match(a.Value), nil
+		default:
+			return false, UnsupportedTypeError{Type: a.Type()}
+		}
+	}
+}
+
+// stringPair returns a predicate that checks a pair of string values with test.
+// Pairs involving null never match, pairs of different types return a
+// TypeMismatchError and pairs of other types return an UnsupportedTypeError.
+func stringPair(test func(a, b string) bool) func(a, b Value) (bool, error) {
+	return func(a, b Value) (bool, error) {
+		if a.Type() == ValueType_Null || b.Type() == ValueType_Null {
+			return false, nil
+		}
+
+		if a.Type() != b.Type() {
+			return false, TypeMismatchError{Left: a.Type(), Right: b.Type()}
+		}
+
+		s, ok := a.(StringValue)
+
+		if !ok {
+			return false, UnsupportedTypeError{Type: a.Type()}
+		}
+
+		return test(s.Value, b.(StringValue).Value), nil
+	}
+}
+
+// EvaluateNotOperation returns the inverse of applying the not operation's
+// operation to the current value.
+// Because operations on a MultiValue match if any of its values match, a not
+// operation on a MultiValue matches only if none of its values match.
+func (e *Evaluator) EvaluateNotOperation(not parser.NotOperationNode, current, input Value) (result Value, err error) {
+	value, err := e.EvaluateOperation(not.Operation, current, input)
+
+	if err != nil {
+		err = fmt.Errorf("failed to evaluate operation: %w", err)
+		return
+	}
+
+	return invert(value)
+}
+
+// ordered returns a predicate that compares two values and checks the result
+// of the comparison with test.
+// Null is not ordered against anything, so comparisons involving null are
+// always false. Comparing values of different types returns a
+// TypeMismatchError.
+func ordered(test func(c int) bool) func(a, b Value) (bool, error) {
+	return func(a, b Value) (bool, error) {
+		if a.Type() == ValueType_Null || b.Type() == ValueType_Null {
+			return false, nil
+		}
+
+		c, err := Compare(a, b)
+
+		if err != nil {
+			return false, err
+		}
+
+		return test(c), nil
+	}
+}
+
+// invert returns the inverse of a boolean value.
+// If the value is not a boolean, invert returns an UnsupportedTypeError.
+func invert(value Value) (result Value, err error) {
+	boolean, ok := value.(BooleanValue)
+
+	if !ok {
+		err = UnsupportedTypeError{Type: value.Type()}
+		return
+	}
+
+	return BooleanValue{Value: !boolean.Value}, nil
+}
+
+// anyValue returns whether the predicate holds for any of the values held by
+// value, in the same way as anyPair.
+func anyValue(value Value, predicate func(a Value) (bool, error)) (result Value, err error) {
+	return anyPair(value, NullValue{}, func(a, _ Value) (bool, error) {
+		return predicate(a)
+	})
+}
+
+// anyPair returns whether the predicate holds for any pairing of the left and
+// right values.
+// Values that hold multiple values contribute each of their values to the
+// pairings, so an operation on a multi-valued result matches if any one of its
+// values matches. A MultiValue with no values never matches.
+func anyPair(left, right Value, predicate func(a, b Value) (bool, error)) (result Value, err error) {
+	for _, a := range values(left) {
+		for _, b := range values(right) {
+			var ok bool
+			ok, err = predicate(a, b)
+
+			if err != nil {
+				return
+			}
+
+			if ok {
+				return BooleanValue{Value: true}, nil
+			}
+		}
+	}
+
+	return BooleanValue{Value: false}, nil
+}
+
+// EvaluateExpression returns the value of the expression.
+func (e *Evaluator) EvaluateExpression(expression parser.Expression, input Value) (result Value, err error) {
+	switch x := expression.(type) {
+	case parser.BlockNode:
+		return e.EvaluateBlock(x, input)
+	case parser.NumberNode:
+		return NumberValue{Value: x.Value}, nil
+	case parser.StringNode:
+		return StringValue{Value: x.Value}, nil
+	case parser.BooleanNode:
+		return BooleanValue{Value: x.Value}, nil
+	case parser.NullNode:
+		return NullValue{}, nil
+	case parser.ListNode:
+		return e.EvaluateList(x, input)
+	case parser.ObjectNode:
+		return e.EvaluateObject(x, input)
+	case parser.CallNode:
+		return e.EvaluateCall(x, input)
+	case parser.ContextNode:
+		return input, nil
+	case parser.LabelNode:
+		return mapPath(input, fieldStep(x.Value))
+	case parser.FieldNode:
+		return e.EvaluateField(x, input)
+	case parser.IndexNode:
+		return e.EvaluateIndex(x, input)
+	case parser.SliceNode:
+		return e.EvaluateSlice(x, input)
+	case parser.WildcardNode:
+		return e.EvaluateWildcard(x, input)
+	case parser.RecursiveNode:
+		return e.EvaluateRecursive(x, input)
+	case parser.FilterNode:
+		return e.EvaluateFilter(x, input)
+	case parser.NotNode:
+		return e.EvaluateNot(x, input)
+	case parser.AndNode:
+		return e.EvaluateAnd(x, input)
+	case parser.OrNode:
+		return e.EvaluateOr(x, input)
+	case parser.AddNode:
+		return e.EvaluateAdd(x, input)
+	case parser.SubtractNode:
+		return e.EvaluateSubtract(x, input)
+	case parser.MultiplyNode:
+		return e.EvaluateMultiply(x, input)
+	case parser.DivideNode:
+		return e.EvaluateDivide(x, input)
+	case parser.ModuloNode:
+		return e.EvaluateModulo(x, input)
+	case parser.NegateNode:
+		return e.EvaluateNegate(x, input)
+	default:
+		err = fmt.Errorf("unsupported expression type: %s", parser.NodeTypeString[expression.Type()])
+		return
+	}
+}
+
+// EvaluateList returns a ListValue holding the values of the list node's
+// elements.
+// Elements that evaluate to a MultiValue are stored as a list of its values.
+func (e *Evaluator) EvaluateList(list parser.ListNode, input Value) (result Value, err error) {
+	elements := make([]Value, len(list.Elements))
+
+	for i, element := range list.Elements {
+		elements[i], err = e.evaluateElement(element, input)
+
+		if err != nil {
+			err = fmt.Errorf("failed to evaluate element %d: %w", i, err)
+			return
+		}
+	}
+
+	return ListValue{Value: elements}, nil
+}
+
+// EvaluateObject returns an ObjectValue holding the values of the object
+// node's fields.
+// Fields that evaluate to a MultiValue are stored as a list of its values.
+func (e *Evaluator) EvaluateObject(object parser.ObjectNode, input Value) (result Value, err error) {
+	fields := make(map[string]Value, len(object.Fields))
+
+	for key, field := range object.Fields {
+		fields[key], err = e.evaluateElement(field, input)
+
+		if err != nil {
+			err = fmt.Errorf("failed to evaluate field %q: %w", key, err)
+			return
+		}
+	}
+
+	return ObjectValue{Value: fields}, nil
+}
+
+// evaluateElement returns the value of an expression that is stored in a list
+// or object, converting a MultiValue into a list of its values.
+func (e *Evaluator) evaluateElement(expression parser.Expression, input Value) (result Value, err error) {
+	result, err = e.EvaluateExpression(expression, input)
+
+	if err != nil {
+		return
+	}
+
+	if multi, ok := result.(MultiValue); ok {
+		return ListValue{Value: multi.Value}, nil
+	}
+
+	return result, nil
+}
+
+// EvaluateNot returns the inverse of the value of the not node's condition.
+func (e *Evaluator) EvaluateNot(not parser.NotNode, input Value) (result Value, err error) {
+	value, err := e.EvaluateExpression(not.Expression, input)
+
+	if err != nil {
+		err = fmt.Errorf("failed to evaluate condition: %w", err)
+		return
+	}
+
+	return invert(value)
+}
+
+// EvaluateAnd returns whether both of the and node's conditions are true.
+// The right condition is only evaluated if the left condition is true.
+func (e *Evaluator) EvaluateAnd(and parser.AndNode, input Value) (result Value, err error) {
+	left, err := e.evaluateCondition(and.Left, input)
+
+	if err != nil {
+		err = fmt.Errorf("failed to evaluate left side of And: %w", err)
+		return
+	}
+
+	if !left {
+		return BooleanValue{Value: false}, nil
+	}
+
+	right, err := e.evaluateCondition(and.Right, input)
+
+	if err != nil {
+		err = fmt.Errorf("failed to evaluate right side of And: %w", err)
+		return
+	}
+
+	return BooleanValue{Value: right}, nil
+}
+
+// EvaluateOr returns whether either of the or node's conditions are true.
+// The right condition is only evaluated if the left condition is false.
+func (e *Evaluator) EvaluateOr(or parser.OrNode, input Value) (result Value, err error) {
+	left, err := e.evaluateCondition(or.Left, input)
+
+	if err != nil {
+		err = fmt.Errorf("failed to evaluate left side of Or: %w", err)
+		return
+	}
+
+	if left {
+		return BooleanValue{Value: true}, nil
+	}
+
+	right, err := e.evaluateCondition(or.Right, input)
+
+	if err != nil {
+		err = fmt.Errorf("failed to evaluate right side of Or: %w", err)
+		return
+	}
+
+	return BooleanValue{Value: right}, nil
+}
+
+// evaluateCondition returns the value of an expression that must evaluate to a
+// boolean.
+func (e *Evaluator) evaluateCondition(condition parser.Expression, input Value) (result bool, err error) {
+	value, err := e.EvaluateExpression(condition, input)
+
+	if err != nil {
+		return
+	}
+
+	boolean, ok := value.(BooleanValue)
+
+	if !ok {
+		err = UnsupportedTypeError{Type: value.Type()}
+		return
+	}
+
+	return boolean.Value, nil
+}
@@ -0,0 +1,1096 @@
+package evaluator
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/fcutting/fpath/internal/lexer"
+	"github.com/fcutting/fpath/internal/parser"
+	"github.com/gkampitakis/go-snaps/snaps"
+)
+
+func TestMain(m *testing.M) {
+	r := m.Run()
+	snaps.Clean(m, snaps.CleanOpts{Sort: true})
+	os.Exit(r)
+}
+
+func Test_Evaluator_EvaluateExpression(t *testing.T) {
+	testCases := map[string]struct {
+		input string
+		data  any
+	}{
+		"Number": {
+			input: "2",
+		},
+		"Equals true": {
+			input: "2 equals 2",
+		},
+		"Equals false": {
+			input: "2 equals 4",
+		},
+		"Equals decimal": {
+			input: "2 equals 2.0",
+		},
+		"Equals exponent": {
+			input: "1.5e3 equals 1_500",
+		},
+		"Equals hexadecimal": {
+			input: "0xff equals 255",
+		},
+		"Greater negative": {
+			input: "-1.5 greater -2",
+		},
+		"Chained equals": {
+			input: "2 equals 4 equals 4",
+		},
+		"Label": {
+			input: "total equals 100",
+			data:  map[string]any{"total": 100},
+		},
+		"Path": {
+			input: "order.customer.email",
+			data: map[string]any{
+				"order": map[string]any{
+					"customer": map[string]any{"email": "bob@example.com"},
+				},
+			},
+		},
+		"Path missing": {
+			input: "order.customer.email",
+			data:  map[string]any{"order": map[string]any{}},
+		},
+		"Path not object": {
+			input: "order.total",
+			data:  map[string]any{"order": 5},
+		},
+		"Index": {
+			input: "items[1].sku",
+			data:  map[string]any{"items": []any{map[string]any{"sku": "a"}, map[string]any{"sku": "b"}}},
+		},
+		"Negative index": {
+			input: "items[-1]",
+			data:  map[string]any{"items": []any{1, 2, 3}},
+		},
+		"Index out of range": {
+			input: "items[3]",
+			data:  map[string]any{"items": []any{1, 2, 3}},
+		},
+		"Index overflow": {
+			input: "items[18446744073709551617]",
+			data:  map[string]any{"items": []any{1, 2, 3}},
+		},
+		"Negative index overflow": {
+			input: "items[-18446744073709551615]",
+			data:  map[string]any{"items": []any{1, 2, 3}},
+		},
+		"Index not list": {
+			input: "items[0]",
+			data:  map[string]any{"items": "abc"},
+		},
+		"Slice": {
+			input: "items[1:3]",
+			data:  map[string]any{"items": []any{1, 2, 3, 4}},
+		},
+		"Slice negative": {
+			input: "items[-2:]",
+			data:  map[string]any{"items": []any{1, 2, 3, 4}},
+		},
+		"Slice open start": {
+			input: "items[:1]",
+			data:  map[string]any{"items": []any{1, 2, 3, 4}},
+		},
+		"Slice clamped": {
+			input: "items[2:10]",
+			data:  map[string]any{"items": []any{1, 2, 3, 4}},
+		},
+		"Slice overflow": {
+			input: "items[18446744073709551616:18446744073709551618]",
+			data:  map[string]any{"items": []any{1, 2, 3, 4}},
+		},
+		"Slice negative overflow": {
+			input: "items[-18446744073709551616:2]",
+			data:  map[string]any{"items": []any{1, 2, 3, 4}},
+		},
+		"Slice empty": {
+			input: "items[3:1]",
+			data:  map[string]any{"items": []any{1, 2, 3, 4}},
+		},
+		"Wildcard list": {
+			input: "items[*]",
+			data:  map[string]any{"items": []any{1, 2}},
+		},
+		"Wildcard object": {
+			input: "prices[*]",
+			data:  map[string]any{"prices": map[string]any{"b": 2, "a": 1}},
+		},
+		"Wildcard scalar": {
+			input: "items[*]",
+			data:  map[string]any{"items": 1},
+		},
+		"Wildcard field": {
+			input: "items[*].sku",
+			data: map[string]any{"items": []any{
+				map[string]any{"sku": "a"},
+				map[string]any{},
+				map[string]any{"sku": nil},
+				map[string]any{"sku": "b"},
+			}},
+		},
+		"Wildcard nested": {
+			input: "orders[*].items[*]",
+			data: map[string]any{"orders": []any{
+				map[string]any{"items": []any{1, 2}},
+				map[string]any{"items": []any{3}},
+			}},
+		},
+		"Wildcard index": {
+			input: "orders[*].items[-1]",
+			data: map[string]any{"orders": []any{
+				map[string]any{"items": []any{1, 2}},
+				map[string]any{"items": []any{}},
+				map[string]any{"items": []any{3}},
+			}},
+		},
+		"Recursive": {
+			input: "..id",
+			data: map[string]any{
+				"id": 1,
+				"order": map[string]any{
+					"id":    2,
+					"items": []any{map[string]any{"id": 3}, map[string]any{"id": 4}},
+				},
+			},
+		},
+		"Recursive path": {
+			input: "order..id",
+			data: map[string]any{
+				"id":    1,
+				"order": map[string]any{"items": []any{map[string]any{"id": 3}}},
+			},
+		},
+		"Multi equals any": {
+			input: "items[*].id equals 2",
+			data:  map[string]any{"items": []any{map[string]any{"id": 1}, map[string]any{"id": 2}}},
+		},
+		"Multi equals none": {
+			input: "items[*] equals 3",
+			data:  map[string]any{"items": []any{1, 2}},
+		},
+		"Filter": {
+			input: "items[price equals 10].sku",
+			data: map[string]any{"items": []any{
+				map[string]any{"sku": 1, "price": 10},
+				map[string]any{"sku": 2, "price": 5},
+				map[string]any{"sku": 3, "price": 10},
+			}},
+		},
+		"Filter nested": {
+			input: "orders[items[*].sku equals 2].id",
+			data: map[string]any{"orders": []any{
+				map[string]any{"id": 1, "items": []any{map[string]any{"sku": 1}}},
+				map[string]any{"id": 2, "items": []any{map[string]any{"sku": 1}, map[string]any{"sku": 2}}},
+			}},
+		},
+		"Filter no match": {
+			input: "items[price equals 1]",
+			data:  map[string]any{"items": []any{map[string]any{"price": 10}}},
+		},
+		"Filter equals": {
+			input: "items[price equals 10].sku equals 3",
+			data: map[string]any{"items": []any{
+				map[string]any{"sku": 1, "price": 10},
+				map[string]any{"sku": 3, "price": 10},
+			}},
+		},
+		"Keyword field": {
+			input: `order.contains contains "x" and .in equals 1`,
+			data:  map[string]any{"order": map[string]any{"contains": "xyz"}, "in": 1},
+		},
+		"Context": {
+			input: ".",
+			data:  map[string]any{"a": 1},
+		},
+		"Filter scalar strings": {
+			input: `tags[. equals "beta"]`,
+			data:  map[string]any{"tags": []any{"alpha", "beta", "gamma"}},
+		},
+		"Filter scalar numbers": {
+			input: "scores[. greater 10]",
+			data:  map[string]any{"scores": []any{5, 12, 10, 40}},
+		},
+		"Filter root list": {
+			input: ".[. lesser 0]",
+			data:  []any{1, -2, 3, -4},
+		},
+		"Filter path": {
+			input: "items[active].id",
+			data: map[string]any{"items": []any{
+				map[string]any{"id": 1, "active": true},
+				map[string]any{"id": 2, "active": false},
+				map[string]any{"id": 3},
+				map[string]any{"id": 4, "active": "yes"},
+			}},
+		},
+		"Filter scalar booleans": {
+			input: "flags[.]",
+			data:  map[string]any{"flags": []any{true, false, nil, true}},
+		},
+		"Contains substring": {
+			input: "name contains part",
+			data:  map[string]any{"name": "fletcher", "part": "etch"},
+		},
+		"Contains substring false": {
+			input: "name contains part",
+			data:  map[string]any{"name": "fletcher", "part": "bob"},
+		},
+		"Contains element": {
+			input: "items contains 2",
+			data:  map[string]any{"items": []any{1, 2}},
+		},
+		"Contains element false": {
+			input: "items contains 3",
+			data:  map[string]any{"items": []any{1, 2}},
+		},
+		"Contains key": {
+			input: "order contains key",
+			data:  map[string]any{"order": map[string]any{"id": 1}, "key": "id"},
+		},
+		"Contains key false": {
+			input: "order contains key",
+			data:  map[string]any{"order": map[string]any{"id": 1}, "key": "total"},
+		},
+		"Contains null": {
+			input: "missing contains 1",
+			data:  map[string]any{},
+		},
+		"Contains multi": {
+			input: "orders[*].items contains 3",
+			data: map[string]any{"orders": []any{
+				map[string]any{"items": []any{1, 2}},
+				map[string]any{"items": []any{3}},
+			}},
+		},
+		"Greater": {
+			input: "order.total greater 100",
+			data:  map[string]any{"order": map[string]any{"total": 100.01}},
+		},
+		"Greater equal": {
+			input: "order.total greater 100",
+			data:  map[string]any{"order": map[string]any{"total": 100}},
+		},
+		"Greater exact": {
+			input: "a greater b",
+			data:  map[string]any{"a": json.Number("0.30000000000000000001"), "b": json.Number("0.3")},
+		},
+		"Greater string": {
+			input: "a greater b",
+			data:  map[string]any{"a": "banana", "b": "apple"},
+		},
+		"Greater null": {
+			input: "missing greater 1",
+			data:  map[string]any{},
+		},
+		"Lesser": {
+			input: "order.total lesser 100",
+			data:  map[string]any{"order": map[string]any{"total": 99}},
+		},
+		"Lesser string": {
+			input: "a lesser b",
+			data:  map[string]any{"a": "banana", "b": "apple"},
+		},
+		"Lesser multi": {
+			input: "items[*].price lesser 10",
+			data:  map[string]any{"items": []any{map[string]any{"price": 20}, map[string]any{"price": 5}}},
+		},
+		"Filter greater": {
+			input: "items[price greater 10].sku",
+			data: map[string]any{"items": []any{
+				map[string]any{"sku": 1, "price": 20},
+				map[string]any{"sku": 2, "price": 5},
+				map[string]any{"sku": 3},
+			}},
+		},
+		"Not equals": {
+			input: "status not equals 1",
+			data:  map[string]any{"status": 2},
+		},
+		"Not contains": {
+			input: "tags not contains tag",
+			data:  map[string]any{"tags": []any{"alpha", "beta"}, "tag": "beta"},
+		},
+		"Not multi": {
+			input: "items[*] not equals 2",
+			data:  map[string]any{"items": []any{1, 2}},
+		},
+		"Not multi none": {
+			input: "items[*] not equals 3",
+			data:  map[string]any{"items": []any{1, 2}},
+		},
+		"Not": {
+			input: "not (status equals 1)",
+			data:  map[string]any{"status": 1},
+		},
+		"Not double": {
+			input: "not (not (status equals 1))",
+			data:  map[string]any{"status": 1},
+		},
+		"Not filter": {
+			input: "items[not (price greater 10)].sku",
+			data: map[string]any{"items": []any{
+				map[string]any{"sku": 1, "price": 20},
+				map[string]any{"sku": 2, "price": 5},
+			}},
+		},
+		"And": {
+			input: "age greater 18 and country equals 64",
+			data:  map[string]any{"age": 30, "country": 64},
+		},
+		"And false": {
+			input: "age greater 18 and country equals 64",
+			data:  map[string]any{"age": 30, "country": 61},
+		},
+		"And short circuit": {
+			input: "age greater 18 and country greater 1",
+			data:  map[string]any{"age": 10, "country": "NZ"},
+		},
+		"Or": {
+			input: "a equals 1 or b equals 2",
+			data:  map[string]any{"a": 0, "b": 2},
+		},
+		"Or false": {
+			input: "a equals 1 or b equals 2",
+			data:  map[string]any{"a": 0, "b": 0},
+		},
+		"Or short circuit": {
+			input: "a equals 1 or b greater 1",
+			data:  map[string]any{"a": 1, "b": "NZ"},
+		},
+		"Precedence": {
+			input: "a equals 1 or b equals 2 and c equals 3",
+			data:  map[string]any{"a": 1, "b": 0, "c": 0},
+		},
+		"Boolean field": {
+			input: "active and age greater 18",
+			data:  map[string]any{"active": true, "age": 30},
+		},
+		"Filter and": {
+			input: "items[price greater 10 and stock greater 0].sku",
+			data: map[string]any{"items": []any{
+				map[string]any{"sku": 1, "price": 20, "stock": 0},
+				map[string]any{"sku": 2, "price": 20, "stock": 5},
+				map[string]any{"sku": 3, "price": 5, "stock": 5},
+			}},
+		},
+		"Group": {
+			input: "(a equals 1 or b equals 2) and c equals 3",
+			data:  map[string]any{"a": 0, "b": 2, "c": 0},
+		},
+		"Group precedence": {
+			input: "a equals 1 or (b equals 2 and c equals 3)",
+			data:  map[string]any{"a": 1, "b": 0, "c": 0},
+		},
+		"Group operations": {
+			input: "(a equals 1) equals b",
+			data:  map[string]any{"a": 1, "b": true},
+		},
+		"Not without group": {
+			input: "not a equals 1 and b equals 2",
+			data:  map[string]any{"a": 0, "b": 2},
+		},
+		"String": {
+			input: `"bob"`,
+		},
+		"String equals": {
+			input: `name equals "bob"`,
+			data:  map[string]any{"name": "bob"},
+		},
+		"String equals case": {
+			input: `name equals "bob"`,
+			data:  map[string]any{"name": "Bob"},
+		},
+		"String contains": {
+			input: `name contains "et"`,
+			data:  map[string]any{"name": "fletcher"},
+		},
+		"String list contains": {
+			input: `tags contains "beta"`,
+			data:  map[string]any{"tags": []any{"alpha", "beta"}},
+		},
+		"String key contains": {
+			input: `order contains "total"`,
+			data:  map[string]any{"order": map[string]any{"total": 1}},
+		},
+		"String greater": {
+			input: `name greater "alice"`,
+			data:  map[string]any{"name": "bob"},
+		},
+		"String lesser": {
+			input: `"apple" lesser "apples"`,
+		},
+		"String escapes": {
+			input: `name equals 'O\'Brien' and quote equals "say \"hi\"\n"`,
+			data:  map[string]any{"name": "O'Brien", "quote": "say \"hi\"\n"},
+		},
+		"String raw": {
+			input: "path contains `\\d`",
+			data:  map[string]any{"path": `a\db`},
+		},
+		"String filter": {
+			input: `items[status equals "open"].id`,
+			data: map[string]any{"items": []any{
+				map[string]any{"id": 1, "status": "open"},
+				map[string]any{"id": 2, "status": "closed"},
+			}},
+		},
+		"Multi equals empty": {
+			input: "items[*] equals 3",
+			data:  map[string]any{"items": []any{}},
+		},
+		"Greater equals": {
+			input: "2 >= 2 and 3 >= 2 and not (1 >= 2)",
+		},
+		"Lesser equals": {
+			input: "2 <= 2 and 1 <= 2 and not (3 <= 2)",
+		},
+		"Greater equals null": {
+			input: "missing >= 0",
+		},
+		"Quoted label": {
+			input: `person."first name" equals "Ada"`,
+			data:  map[string]any{"person": map[string]any{"first name": "Ada"}},
+		},
+		"Quoted keyword": {
+			input: `."equals".'not'`,
+			data:  map[string]any{"equals": map[string]any{"not": 1}},
+		},
+		"Quoted recursive": {
+			input: `..'content-type'`,
+			data: map[string]any{
+				"request":  map[string]any{"headers": map[string]any{"content-type": "text/plain"}},
+				"response": map[string]any{"headers": map[string]any{"content-type": "text/html"}},
+			},
+		},
+		"String index": {
+			input: `headers["content-type"]`,
+			data:  map[string]any{"headers": map[string]any{"content-type": "text/plain"}},
+		},
+		"String index multi": {
+			input: `items[*]["sku-id"]`,
+			data:  map[string]any{"items": []any{map[string]any{"sku-id": 1}, map[string]any{"sku-id": 2}}},
+		},
+		"String index not object": {
+			input: `items["0"]`,
+			data:  map[string]any{"items": []any{1}},
+		},
+		"Equals true literal": {
+			input: "active equals true",
+			data:  map[string]any{"active": true},
+		},
+		"Equals false literal": {
+			input: "active equals false",
+			data:  map[string]any{"active": true},
+		},
+		"Equals null": {
+			input: "deleted_at equals null",
+			data:  map[string]any{"deleted_at": nil},
+		},
+		"Equals null missing": {
+			input: "deleted_at equals null",
+		},
+		"Not equals null": {
+			input: "deleted_at != null",
+			data:  map[string]any{"deleted_at": "2024-01-01"},
+		},
+		"Null contains": {
+			input: "null contains 1",
+		},
+		"String contains null": {
+			input: `"abc" contains null`,
+		},
+		"Object contains null": {
+			input: "order contains null",
+			data:  map[string]any{"order": map[string]any{}},
+		},
+		"List contains null": {
+			input: "tags contains null",
+			data:  map[string]any{"tags": []any{"a", nil}},
+		},
+		"Ordered null literal": {
+			input: "1 greater null or 1 lesser null or null >= null",
+		},
+		"Null arithmetic": {
+			input: "null * 2",
+		},
+		"Boolean condition": {
+			input: "true and not false",
+		},
+		"List literal": {
+			input: `[1, "a", [true], null]`,
+		},
+		"List literal equals": {
+			input: `tags equals ["a", "b"]`,
+			data:  map[string]any{"tags": []any{"a", "b"}},
+		},
+		"List literal contains": {
+			input: `["open", "pending"] contains status`,
+			data:  map[string]any{"status": "open"},
+		},
+		"List literal multi": {
+			input: "[items[*].id, 3]",
+			data:  map[string]any{"items": []any{map[string]any{"id": 1}, map[string]any{"id": 2}}},
+		},
+		"Object literal": {
+			input: `{"total": price * 2, name: name}`,
+			data:  map[string]any{"price": 1.5, "name": "bob"},
+		},
+		"Object literal equals": {
+			input: `order equals {"a": 1, "b": [2]}`,
+			data:  map[string]any{"order": map[string]any{"b": []any{2}, "a": 1}},
+		},
+		"In": {
+			input: "code in (200 201 204)",
+			data:  map[string]any{"code": 201},
+		},
+		"In missing": {
+			input: "code in (200, 201, 204)",
+			data:  map[string]any{"code": 404},
+		},
+		"In list": {
+			input: `status in ["open", "pending"]`,
+			data:  map[string]any{"status": "pending"},
+		},
+		"In string": {
+			input: `"ell" in "hello"`,
+		},
+		"In object": {
+			input: `"a" in order`,
+			data:  map[string]any{"order": map[string]any{"a": 1}},
+		},
+		"In null": {
+			input: "null in (1 null)",
+		},
+		"In negative": {
+			input: "x in (-1, -2)",
+			data:  map[string]any{"x": -2},
+		},
+		"In negative not difference": {
+			input: "x in (-1, -2)",
+			data:  map[string]any{"x": -3},
+		},
+		"In arithmetic": {
+			input: "x in (a - 1, a + 1)",
+			data:  map[string]any{"x": 4, "a": 5},
+		},
+		"In single field": {
+			input: "v in (tags)",
+			data:  map[string]any{"v": "a", "tags": []any{"a", "b"}},
+		},
+		"In single field list": {
+			input: "v in (tags)",
+			data:  map[string]any{"v": []any{"a", "b"}, "tags": []any{"a", "b"}},
+		},
+		"Not in": {
+			input: "code not in (200 201)",
+			data:  map[string]any{"code": 500},
+		},
+		"In multi": {
+			input: "items[*].code in (3 4)",
+			data:  map[string]any{"items": []any{map[string]any{"code": 1}, map[string]any{"code": 4}}},
+		},
+		"Between": {
+			input: "latency between 100 and 500",
+			data:  map[string]any{"latency": 100},
+		},
+		"Between upper": {
+			input: "latency between 100 and 500",
+			data:  map[string]any{"latency": 500},
+		},
+		"Between outside": {
+			input: "latency between 100 and 500",
+			data:  map[string]any{"latency": 501},
+		},
+		"Between strings": {
+			input: `name between "a" and "c"`,
+			data:  map[string]any{"name": "bob"},
+		},
+		"Between null": {
+			input: "missing between 1 and 2",
+		},
+		"Between and": {
+			input: "latency between 100 and 500 and code in (200)",
+			data:  map[string]any{"latency": 200, "code": 200},
+		},
+		"Matches": {
+			input: "path matches `^/api/v[0-9]+`",
+			data:  map[string]any{"path": "/api/v2/users"},
+		},
+		"Matches unanchored": {
+			input: "path matches `v[0-9]+`",
+			data:  map[string]any{"path": "/api/v2/users"},
+		},
+		"Matches no match": {
+			input: "path matches `^/api/v[0-9]+`",
+			data:  map[string]any{"path": "/web/v2"},
+		},
+		"Matches null": {
+			input: `missing matches "."`,
+		},
+		"Matches multi": {
+			input: `paths[*] matches "^/admin"`,
+			data:  map[string]any{"paths": []any{"/api", "/admin/users"}},
+		},
+		"Starts with": {
+			input: `path startswith "/api"`,
+			data:  map[string]any{"path": "/api/v2"},
+		},
+		"Ends with": {
+			input: `path ends with ".json" and not (path ends with ".xml")`,
+			data:  map[string]any{"path": "/data.json"},
+		},
+		"Starts with null": {
+			input: `missing starts with "a"`,
+		},
+		"Like": {
+			input: `name like "J_n%"`,
+			data:  map[string]any{"name": "Jane Doe"},
+		},
+		"Like whole": {
+			input: `name like "an"`,
+			data:  map[string]any{"name": "Jane"},
+		},
+		"Like escape": {
+			input: "discount like `100\\%` and code like `a\\_b`",
+			data:  map[string]any{"discount": "100%", "code": "a_b"},
+		},
+		"Like escape literal": {
+			input: "discount like `100\\%`",
+			data:  map[string]any{"discount": "1000"},
+		},
+		"Like metacharacters": {
+			input: `name like "a.b(%"`,
+			data:  map[string]any{"name": "a.b(c"},
+		},
+		"Like newline": {
+			input: `text like "a%b"`,
+			data:  map[string]any{"text": "a\nb"},
+		},
+		"Extract group": {
+			input: "message extract `user=(\\w+)`",
+			data:  map[string]any{"message": "login user=bob ok user=alice"},
+		},
+		"Extract match": {
+			input: "message extract `\\d+`",
+			data:  map[string]any{"message": "took 250ms"},
+		},
+		"Extract groups": {
+			input: "message extract `(\\w+)=(\\w+)(;)?`",
+			data:  map[string]any{"message": "user=bob"},
+		},
+		"Extract named": {
+			input: "message extract `(?P<key>\\w+)=(?P<value>\\w+) (\\d+)`",
+			data:  map[string]any{"message": "user=bob 42"},
+		},
+		"Extract no match": {
+			input: "message extract `user=(\\w+)`",
+			data:  map[string]any{"message": "anonymous"},
+		},
+		"Extract null": {
+			input: "missing extract `(.*)`",
+		},
+		"Extract multi": {
+			input: "lines[*] extract `id=(\\d+)`",
+			data:  map[string]any{"lines": []any{"id=1", "none", "id=2"}},
+		},
+		"Extract operation": {
+			input: "message extract `user=(\\w+)` equals \"bob\"",
+			data:  map[string]any{"message": "user=bob"},
+		},
+		"Extract filter": {
+			input: "lines[text extract `level=(\\w+)` equals \"error\"].id",
+			data: map[string]any{"lines": []any{
+				map[string]any{"id": 1, "text": "level=info"},
+				map[string]any{"id": 2, "text": "level=error"},
+			}},
+		},
+		"Length string": {
+			input: `length("héllo")`,
+		},
+		"Length list": {
+			input: "length(items)",
+			data:  map[string]any{"items": []any{1, 2, 3}},
+		},
+		"Length object": {
+			input: "length(order)",
+			data:  map[string]any{"order": map[string]any{"a": 1}},
+		},
+		"Length multi": {
+			input: "length(items[price greater 1])",
+			data:  map[string]any{"items": []any{map[string]any{"price": 1}, map[string]any{"price": 2}}},
+		},
+		"Lower": {
+			input: `lower(name) equals "bob"`,
+			data:  map[string]any{"name": "BoB"},
+		},
+		"Upper": {
+			input: `upper("abc")`,
+		},
+		"Trim": {
+			input: `trim(" \t padded \n")`,
+		},
+		"Round": {
+			input: "round(price, 2)",
+			data:  map[string]any{"price": 2.345},
+		},
+		"Round default": {
+			input: "round(-2.5)",
+		},
+		"Round negative places": {
+			input: "round(1234, -2)",
+		},
+		"Abs": {
+			input: "abs(-1.5)",
+		},
+		"Floor ceil": {
+			input: "[floor(1.5), ceil(1.5)]",
+		},
+		"Split": {
+			input: `split(path, "/")`,
+			data:  map[string]any{"path": "a/b/c"},
+		},
+		"Join": {
+			input: `join(tags, ",")`,
+			data:  map[string]any{"tags": []any{"a", "b"}},
+		},
+		"Join split": {
+			input: `join(split("a b c", " "), "-")`,
+		},
+		"Keys": {
+			input: "keys(order)",
+			data:  map[string]any{"order": map[string]any{"b": 1, "a": 2}},
+		},
+		"Sum": {
+			input: "sum(items[*].price)",
+			data:  map[string]any{"items": []any{map[string]any{"price": 1.5}, map[string]any{"price": 2}}},
+		},
+		"Call null": {
+			input: "lower(missing)",
+		},
+		"Call filter": {
+			input: `users[lower(name) startswith "a"].id`,
+			data: map[string]any{"users": []any{
+				map[string]any{"id": 1, "name": "Alice"},
+				map[string]any{"id": 2, "name": "bob"},
+			}},
+		},
+		"Phrases": {
+			input: "age at least 18 and age at most 65 and score greater than or equal to 50",
+			data:  map[string]any{"age": 18, "score": 50},
+		},
+		"Symbolic": {
+			input: `name != "bob" and tags ~ "beta" and age > 17 and age < 65`,
+			data:  map[string]any{"name": "alice", "tags": []any{"beta"}, "age": 30},
+		},
+		"Add": {
+			input: "0.1 + 0.2",
+		},
+		"Subtract": {
+			input: "2 - 5",
+		},
+		"Multiply": {
+			input: "1.5 * 3",
+		},
+		"Divide": {
+			input: "10 / 4",
+		},
+		"Divide repeating": {
+			input: "1 / 3",
+		},
+		"Modulo": {
+			input: "-7 % 3",
+		},
+		"Negate": {
+			input: "-price",
+			data:  map[string]any{"price": 5},
+		},
+		"Arithmetic precedence": {
+			input: "2 + 3 * 4 - 6 / 2",
+		},
+		"Arithmetic group": {
+			input: "(total - discount) / 2",
+			data:  map[string]any{"total": 100, "discount": 20},
+		},
+		"Arithmetic operation": {
+			input: "price * quantity greater 1000",
+			data:  map[string]any{"price": 25.5, "quantity": 40},
+		},
+		"Arithmetic null": {
+			input: "missing + 1",
+		},
+		"Arithmetic multi": {
+			input: "items[*].price * 2",
+			data: map[string]any{"items": []any{
+				map[string]any{"price": 1},
+				map[string]any{"price": 2.5},
+			}},
+		},
+		"Arithmetic filter": {
+			input: "items[price * quantity greater 10].id",
+			data: map[string]any{"items": []any{
+				map[string]any{"id": 1, "price": 3, "quantity": 3},
+				map[string]any{"id": 2, "price": 3, "quantity": 4},
+			}},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			lexer := lexer.NewLexer(tc.input)
+			parser := parser.NewParser(lexer)
+			query, err := parser.ParseQuery()
+
+			if err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+
+			data, err := NewValue(tc.data)
+
+			if err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+
+			evaluator := NewEvaluator()
+			result, err := evaluator.EvaluateExpression(query, data)
+
+			if err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+
+			snaps.MatchSnapshot(t, result.String())
+		})
+	}
+}
+
+func Test_Evaluator_EvaluateExpression_Error(t *testing.T) {
+	testCases := map[string]struct {
+		input string
+		data  any
+	}{
+		"Index not number": {
+			input: "items[true]",
+			data:  map[string]any{"items": []any{1}},
+		},
+		"Index not integer": {
+			input: "items[0.5]",
+			data:  map[string]any{"items": []any{1}},
+		},
+		"Slice not integer": {
+			input: "items[index:]",
+			data:  map[string]any{"items": []any{1}, "index": 0.5},
+		},
+		"Contains number": {
+			input: "2 contains 2",
+		},
+		"Contains string number": {
+			input: "name contains 2",
+			data:  map[string]any{"name": "2"},
+		},
+		"Greater mismatched": {
+			input: "name greater 2",
+			data:  map[string]any{"name": "3"},
+		},
+		"Lesser object": {
+			input: "order lesser order",
+			data:  map[string]any{"order": map[string]any{}},
+		},
+		"Not number": {
+			input: "not (2)",
+		},
+		"And number": {
+			input: "a and b",
+			data:  map[string]any{"a": true, "b": 1},
+		},
+		"Or number": {
+			input: "a or b",
+			data:  map[string]any{"a": 1, "b": true},
+		},
+		"String greater number": {
+			input: `"2" greater 1`,
+		},
+		"Contains object number": {
+			input: "order contains 2",
+			data:  map[string]any{"order": map[string]any{"2": 2}},
+		},
+		"Add string": {
+			input: `name + 1`,
+			data:  map[string]any{"name": "bob"},
+		},
+		"Negate boolean": {
+			input: "-(1 equals 1)",
+		},
+		"Lesser equals mismatched": {
+			input: `1 <= "2"`,
+		},
+		"And null": {
+			input: "true and null",
+		},
+		"List element error": {
+			input: "[1, 1 / 0]",
+		},
+		"In number": {
+			input: "1 in 1",
+		},
+		"Between mismatched": {
+			input: `1 between "a" and 2`,
+		},
+		"Matches number": {
+			input: `1 matches "1"`,
+		},
+		"Starts with mismatched": {
+			input: `name startswith 1`,
+			data:  map[string]any{"name": "1"},
+		},
+		"Ends with list": {
+			input: `tags endswith tags`,
+			data:  map[string]any{"tags": []any{}},
+		},
+		"Extract number": {
+			input: "1 extract `1`",
+		},
+		"Call argument type": {
+			input: "lower(age)",
+			data:  map[string]any{"age": 1},
+		},
+		"Join numbers": {
+			input: `join(items, ",")`,
+			data:  map[string]any{"items": []any{1, 2}},
+		},
+		"Sum strings": {
+			input: `sum(["a"])`,
+		},
+		"Round fractional places": {
+			input: "round(1, 0.5)",
+		},
+		"Round too many places": {
+			input: "round(1.5, 99999999999)",
+		},
+		"Round wrapping places": {
+			input: "round(1.55, 4294967297)",
+		},
+		"Round too few places": {
+			input: "round(1.5, -1001)",
+		},
+		"Divide by zero": {
+			input: "1 / 0",
+		},
+		"Modulo by zero": {
+			input: "1 % (2 - 2)",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			lexer := lexer.NewLexer(tc.input)
+			parser := parser.NewParser(lexer)
+			query, err := parser.ParseQuery()
+
+			if err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+
+			data, err := NewValue(tc.data)
+
+			if err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+
+			evaluator := NewEvaluator()
+			_, err = evaluator.EvaluateExpression(query, data)
+
+			if err == nil {
+				t.Fatalf("Expected error but none returned")
+			}
+
+			snaps.MatchSnapshot(t, err.Error())
+		})
+	}
+}
+
+func Test_Evaluator_EvaluateGreater_TypeMismatchError(t *testing.T) {
+	evaluator := NewEvaluator()
+	greater := parser.GreaterNode{Expression: parser.LabelNode{Value: "b"}}
+	input := ObjectValue{Value: map[string]Value{"b": StringValue{Value: "2"}}}
+	_, err := evaluator.EvaluateGreater(greater, NumberValue{}, input)
+
+	var mismatch TypeMismatchError
+
+	if !errors.As(err, &mismatch) {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if mismatch.Left != ValueType_Number || mismatch.Right != ValueType_String {
+		t.Fatalf("Unexpected types\nExpected: Number and String\nActual: %s and %s", ValueTypeString[mismatch.Left], ValueTypeString[mismatch.Right])
+	}
+}
+
+func Test_Evaluator_EvaluateDivide_DivisionByZeroError(t *testing.T) {
+	evaluator := NewEvaluator()
+	divide := parser.DivideNode{Left: parser.LabelNode{Value: "a"}, Right: parser.LabelNode{Value: "b"}}
+	input := ObjectValue{Value: map[string]Value{"a": NumberValue{}, "b": NumberValue{}}}
+	_, err := evaluator.EvaluateDivide(divide, input)
+
+	if !errors.As(err, &DivisionByZeroError{}) {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+}
+
+func Test_Evaluator_EvaluateDivide_DivisionPrecision(t *testing.T) {
+	testCases := map[string]struct {
+		precision int32
+	}{
+		"Zero": {
+			precision: 0,
+		},
+		"Two": {
+			precision: 2,
+		},
+		"Default": {
+			precision: DefaultDivisionPrecision,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			evaluator := NewEvaluator()
+			evaluator.DivisionPrecision = tc.precision
+			divide := parser.DivideNode{Left: parser.LabelNode{Value: "a"}, Right: parser.LabelNode{Value: "b"}}
+			input, _ := NewValue(map[string]any{"a": 2, "b": 3})
+			result, err := evaluator.EvaluateDivide(divide, input)
+
+			if err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+
+			snaps.MatchSnapshot(t, result.String())
+		})
+	}
+}
+
+func Test_Evaluator_EvaluateCall_ArgumentTypeError(t *testing.T) {
+	evaluator := NewEvaluator()
+	call := parser.CallNode{Name: "abs", Arguments: []parser.Expression{parser.LabelNode{Value: "a"}}}
+	input := ObjectValue{Value: map[string]Value{"a": StringValue{Value: "1"}}}
+	_, err := evaluator.EvaluateCall(call, input)
+
+	var argument ArgumentTypeError
+
+	if !errors.As(err, &argument) {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if argument.Argument != 1 || argument.Type != ValueType_String {
+		t.Fatalf("Unexpected argument\nExpected: 1 String\nActual: %d %s", argument.Argument, ValueTypeString[argument.Type])
+	}
+}
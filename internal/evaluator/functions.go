@@ -0,0 +1,223 @@
+package evaluator
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/fcutting/fpath/internal/parser"
+	"github.com/shopspring/decimal"
+)
+
+// ArgumentTypeError is returned when a function is called with an argument of
+// a type it doesn't accept.
+type ArgumentTypeError struct {
+	Function string
+	Argument int
+	Expected int
+	Type     int
+}
+
+// Error returns a description of the unaccepted argument.
+func (e ArgumentTypeError) Error() string {
+	return fmt.Sprintf("argument %d of function %q must be %s but got %s", e.Argument, e.Function, parser.ArgumentTypeNames(e.Expected), ValueTypeString[e.Type])
+}
+
+// builtins maps the names of the built-in functions to their implementations.
+// Implementations can rely on their arguments being non-null and of the types
+// declared by the function's signature in parser.Functions.
+var builtins = map[string]func(arguments []Value) (Value, error){
+	"length": builtinLength,
+	"lower": func(arguments []Value) (Value, error) {
+		return StringValue{Value: strings.ToLower(arguments[0].(StringValue).Value)}, nil
+	},
+	"upper": func(arguments []Value) (Value, error) {
+		return StringValue{Value: strings.ToUpper(arguments[0].(StringValue).Value)}, nil
+	},
+	"trim": func(arguments []Value) (Value, error) {
+		return StringValue{Value: strings.TrimSpace(arguments[0].(StringValue).Value)}, nil
+	},
+	"split": builtinSplit,
+	"join":  builtinJoin,
+	"abs": func(arguments []Value) (Value, error) {
+		return NumberValue{Value: arguments[0].(NumberValue).Value.Abs()}, nil
+	},
+	"floor": func(arguments []Value) (Value, error) {
+		return NumberValue{Value: arguments[0].(NumberValue).Value.Floor()}, nil
+	},
+	"ceil": func(arguments []Value) (Value, error) {
+		return NumberValue{Value: arguments[0].(NumberValue).Value.Ceil()}, nil
+	},
+	"round": builtinRound,
+	"keys":  builtinKeys,
+	"sum":   builtinSum,
+}
+
+// EvaluateCall returns the result of calling the call node's function with the
+// values of its arguments.
+// Arguments that evaluate to a MultiValue are passed as a list of its values,
+// so "sum(items[*].price)" adds up every price. If any argument is null, the
+// result is null, so missing fields don't cause errors.
+func (e *Evaluator) EvaluateCall(call parser.CallNode, input Value) (result Value, err error) {
+	function, ok := parser.Functions[call.Name]
+	implementation, implemented := builtins[call.Name]
+
+	if !ok || !implemented {
+		err = fmt.Errorf("unknown function %q", call.Name)
+		return
+	}
+
+	arguments := make([]Value, len(call.Arguments))
+
+	for i, argument := range call.Arguments {
+		arguments[i], err = e.evaluateElement(argument, input)
+
+		if err != nil {
+			err = fmt.Errorf("failed to evaluate argument %d of %q: %w", i+1, call.Name, err)
+			return
+		}
+	}
+
+	for i, argument := range arguments {
+		if argument.Type() == ValueType_Null {
+			return NullValue{}, nil
+		}
+
+		if argumentType(argument)&function.Arguments[i] == 0 {
+			err = ArgumentTypeError{Function: call.Name, Argument: i + 1, Expected: function.Arguments[i], Type: argument.Type()}
+			return
+		}
+	}
+
+	result, err = implementation(arguments)
+
+	if err != nil {
+		err = fmt.Errorf("failed to call %q: %w", call.Name, err)
+		return
+	}
+
+	return result, nil
+}
+
+// argumentType returns the argument type flag matching the value's type.
+func argumentType(value Value) int {
+	switch value.Type() {
+	case ValueType_Number:
+		return parser.ArgumentType_Number
+	case ValueType_String:
+		return parser.ArgumentType_String
+	case ValueType_Boolean:
+		return parser.ArgumentType_Boolean
+	case ValueType_List:
+		return parser.ArgumentType_List
+	case ValueType_Object:
+		return parser.ArgumentType_Object
+	default:
+		return 0
+	}
+}
+
+// builtinLength returns the number of characters in a string, elements in a
+// list or fields in an object.
+func builtinLength(arguments []Value) (Value, error) {
+	var length int
+
+	switch a := arguments[0].(type) {
+	case StringValue:
+		length = utf8.RuneCountInString(a.Value)
+	case ListValue:
+		length = len(a.Value)
+	case ObjectValue:
+		length = len(a.Value)
+	}
+
+	return NumberValue{Value: decimal.NewFromInt(int64(length))}, nil
+}
+
+// builtinSplit returns a list of the substrings of a string separated by a
+// separator.
+func builtinSplit(arguments []Value) (Value, error) {
+	parts := strings.Split(arguments[0].(StringValue).Value, arguments[1].(StringValue).Value)
+	elements := make([]Value, len(parts))
+
+	for i, part := range parts {
+		elements[i] = StringValue{Value: part}
+	}
+
+	return ListValue{Value: elements}, nil
+}
+
+// builtinJoin returns the strings in a list joined by a separator.
+// If the list holds anything but strings, builtinJoin returns an
+// UnsupportedTypeError.
+func builtinJoin(arguments []Value) (Value, error) {
+	elements := arguments[0].(ListValue).Value
+	parts := make([]string, len(elements))
+
+	for i, element := range elements {
+		s, ok := element.(StringValue)
+
+		if !ok {
+			return nil, UnsupportedTypeError{Type: element.Type()}
+		}
+
+		parts[i] = s.Value
+	}
+
+	return StringValue{Value: strings.Join(parts, arguments[1].(StringValue).Value)}, nil
+}
+
+// builtinRound returns a number rounded half away from zero to a number of
+// decimal places, which defaults to zero and may be negative to round to tens,
+// hundreds and so on.
+// The number of places is limited to parser.MaxExponent either way, the same
+// limit as the exponent of a number in a query.
+func builtinRound(arguments []Value) (Value, error) {
+	var places int
+
+	if len(arguments) > 1 {
+		var err error
+		places, err = intValue(arguments[1])
+
+		if err != nil {
+			return nil, err
+		}
+
+		if places > parser.MaxExponent || places < -parser.MaxExponent {
+			return nil, fmt.Errorf("places must be between %d and %d but got %s", -parser.MaxExponent, parser.MaxExponent, arguments[1].(NumberValue).Value.String())
+		}
+	}
+
+	return NumberValue{Value: arguments[0].(NumberValue).Value.Round(int32(places))}, nil
+}
+
+// builtinKeys returns the keys of an object in sorted order.
+func builtinKeys(arguments []Value) (Value, error) {
+	keys := arguments[0].(ObjectValue).keys()
+	elements := make([]Value, len(keys))
+
+	for i, key := range keys {
+		elements[i] = StringValue{Value: key}
+	}
+
+	return ListValue{Value: elements}, nil
+}
+
+// builtinSum returns the sum of the numbers in a list.
+// If the list holds anything but numbers, builtinSum returns an
+// UnsupportedTypeError.
+func builtinSum(arguments []Value) (Value, error) {
+	sum := decimal.Zero
+
+	for _, element := range arguments[0].(ListValue).Value {
+		number, ok := element.(NumberValue)
+
+		if !ok {
+			return nil, UnsupportedTypeError{Type: element.Type()}
+		}
+
+		sum = sum.Add(number.Value)
+	}
+
+	return NumberValue{Value: sum}, nil
+}
@@ -0,0 +1,346 @@
+package evaluator
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/fcutting/fpath/internal/parser"
+	"github.com/shopspring/decimal"
+)
+
+// EvaluateField returns the value of the field's label in the value of the
+// field's expression.
+func (e *Evaluator) EvaluateField(field parser.FieldNode, input Value) (result Value, err error) {
+	value, err := e.EvaluateExpression(field.Expression, input)
+
+	if err != nil {
+		err = fmt.Errorf("failed to evaluate expression: %w", err)
+		return
+	}
+
+	return mapPath(value, fieldStep(field.Label))
+}
+
+// EvaluateIndex returns the element at the index's position in the list value
+// of the index's expression.
+// If the index is a string, EvaluateIndex returns the field with that label in
+// the object value of the index's expression instead.
+// If the value is not a list or the position is out of range, EvaluateIndex
+// returns a NullValue.
+func (e *Evaluator) EvaluateIndex(index parser.IndexNode, input Value) (result Value, err error) {
+	value, err := e.EvaluateExpression(index.Expression, input)
+
+	if err != nil {
+		err = fmt.Errorf("failed to evaluate expression: %w", err)
+		return
+	}
+
+	key, err := e.EvaluateExpression(index.Index, input)
+
+	if err != nil {
+		err = fmt.Errorf("failed to evaluate index: %w", err)
+		return
+	}
+
+	if label, ok := key.(StringValue); ok {
+		return mapPath(value, fieldStep(label.Value))
+	}
+
+	position, err := intValue(key)
+
+	if err != nil {
+		err = fmt.Errorf("failed to evaluate index: %w", err)
+		return
+	}
+
+	return mapPath(value, func(value Value) (Value, bool, error) {
+		list, ok := value.(ListValue)
+
+		if !ok {
+			return nil, false, nil
+		}
+
+		i := position
+
+		if i < 0 {
+			i += len(list.Value)
+		}
+
+		if i < 0 || i >= len(list.Value) {
+			return nil, false, nil
+		}
+
+		return list.Value[i], true, nil
+	})
+}
+
+// EvaluateSlice returns the elements between the slice's start and end
+// positions in the list value of the slice's expression.
+// Positions are clamped to the bounds of the list. If the value is not a list,
+// EvaluateSlice returns a NullValue.
+func (e *Evaluator) EvaluateSlice(slice parser.SliceNode, input Value) (result Value, err error) {
+	value, err := e.EvaluateExpression(slice.Expression, input)
+
+	if err != nil {
+		err = fmt.Errorf("failed to evaluate expression: %w", err)
+		return
+	}
+
+	var start, end int
+
+	if slice.Start != nil {
+		if start, err = e.evaluateInt(slice.Start, input); err != nil {
+			err = fmt.Errorf("failed to evaluate start: %w", err)
+			return
+		}
+	}
+
+	if slice.End != nil {
+		if end, err = e.evaluateInt(slice.End, input); err != nil {
+			err = fmt.Errorf("failed to evaluate end: %w", err)
+			return
+		}
+	}
+
+	return mapPath(value, func(value Value) (Value, bool, error) {
+		list, ok := value.(ListValue)
+
+		if !ok {
+			return nil, false, nil
+		}
+
+		from, to := 0, len(list.Value)
+
+		if slice.Start != nil {
+			from = clampPosition(start, len(list.Value))
+		}
+
+		if slice.End != nil {
+			to = clampPosition(end, len(list.Value))
+		}
+
+		if from >= to {
+			return ListValue{Value: []Value{}}, true, nil
+		}
+
+		return ListValue{Value: list.Value[from:to]}, true, nil
+	})
+}
+
+// EvaluateWildcard returns a MultiValue holding every element of the list or
+// object value of the wildcard's expression.
+// Object elements are ordered by key. Values that are neither lists nor objects
+// have no elements.
+func (e *Evaluator) EvaluateWildcard(wildcard parser.WildcardNode, input Value) (result Value, err error) {
+	value, err := e.EvaluateExpression(wildcard.Expression, input)
+
+	if err != nil {
+		err = fmt.Errorf("failed to evaluate expression: %w", err)
+		return
+	}
+
+	return mapPath(value, func(value Value) (Value, bool, error) {
+		return MultiValue{Value: elements(value)}, true, nil
+	})
+}
+
+// EvaluateRecursive returns a MultiValue holding the value of every field with
+// the recursive node's label at any depth within the value of its expression.
+// Values are ordered depth first, visiting object fields in key order.
+func (e *Evaluator) EvaluateRecursive(recursive parser.RecursiveNode, input Value) (result Value, err error) {
+	value, err := e.EvaluateExpression(recursive.Expression, input)
+
+	if err != nil {
+		err = fmt.Errorf("failed to evaluate expression: %w", err)
+		return
+	}
+
+	return mapPath(value, func(value Value) (Value, bool, error) {
+		return MultiValue{Value: descend(value, recursive.Label, nil)}, true, nil
+	})
+}
+
+// EvaluateFilter returns a MultiValue holding every element of the list or
+// object value of the filter's expression for which the filter's condition
+// evaluates to true.
+// The condition is evaluated with each element as its input data, and must
+// evaluate to a boolean.
+func (e *Evaluator) EvaluateFilter(filter parser.FilterNode, input Value) (result Value, err error) {
+	value, err := e.EvaluateExpression(filter.Expression, input)
+
+	if err != nil {
+		err = fmt.Errorf("failed to evaluate expression: %w", err)
+		return
+	}
+
+	return mapPath(value, func(value Value) (Value, bool, error) {
+		matches := []Value{}
+
+		for _, element := range elements(value) {
+			match, err := e.evaluateCondition(filter.Condition, element)
+
+			if err != nil {
+				return nil, false, fmt.Errorf("failed to evaluate condition: %w", err)
+			}
+
+			if match {
+				matches = append(matches, element)
+			}
+		}
+
+		return MultiValue{Value: matches}, true, nil
+	})
+}
+
+// evaluateInt returns the value of an expression that must evaluate to an
+// integer.
+func (e *Evaluator) evaluateInt(expression parser.Expression, input Value) (result int, err error) {
+	value, err := e.EvaluateExpression(expression, input)
+
+	if err != nil {
+		err = fmt.Errorf("failed to evaluate expression: %w", err)
+		return
+	}
+
+	return intValue(value)
+}
+
+// intValue returns the integer held by a value that must be an integral
+// number.
+// Integers too large for an int are clamped to math.MinInt or math.MaxInt, so
+// they stay out of range of any list instead of wrapping around.
+func intValue(value Value) (result int, err error) {
+	number, ok := value.(NumberValue)
+
+	if !ok {
+		err = UnsupportedTypeError{Type: value.Type()}
+		return
+	}
+
+	if !number.Value.IsInteger() {
+		err = fmt.Errorf("number is not an integer: %s", number.Value.String())
+		return
+	}
+
+	if number.Value.LessThan(decimal.NewFromInt(math.MinInt)) {
+		return math.MinInt, nil
+	}
+
+	if number.Value.GreaterThan(decimal.NewFromInt(math.MaxInt)) {
+		return math.MaxInt, nil
+	}
+
+	return int(number.Value.IntPart()), nil
+}
+
+// mapPath applies a path step to a value.
+// If the value is a MultiValue, the step is applied to each of its values and
+// the results are collected into a new MultiValue, skipping values the step
+// didn't find anything in. Otherwise a step that doesn't find anything results
+// in a NullValue.
+func mapPath(value Value, step func(Value) (Value, bool, error)) (result Value, err error) {
+	multi, ok := value.(MultiValue)
+
+	if !ok {
+		result, ok, err = step(value)
+
+		if err != nil {
+			return
+		}
+
+		if !ok {
+			return NullValue{}, nil
+		}
+
+		return result, nil
+	}
+
+	results := []Value{}
+
+	for _, v := range multi.Value {
+		result, ok, err = step(v)
+
+		if err != nil {
+			return
+		}
+
+		if !ok {
+			continue
+		}
+
+		results = append(results, values(result)...)
+	}
+
+	return MultiValue{Value: results}, nil
+}
+
+// fieldStep returns a path step that finds the value stored under the label in
+// an object.
+func fieldStep(label string) func(Value) (Value, bool, error) {
+	return func(value Value) (Value, bool, error) {
+		object, ok := value.(ObjectValue)
+
+		if !ok {
+			return nil, false, nil
+		}
+
+		field, ok := object.Value[label]
+		return field, ok, nil
+	}
+}
+
+// descend appends the value of every field with the label at any depth within
+// the value to found.
+func descend(value Value, label string, found []Value) []Value {
+	if object, ok := value.(ObjectValue); ok {
+		if field, ok := object.Value[label]; ok {
+			found = append(found, field)
+		}
+	}
+
+	for _, element := range elements(value) {
+		found = descend(element, label, found)
+	}
+
+	return found
+}
+
+// elements returns the elements of a list, or the values of an object ordered
+// by key. Other values have no elements.
+func elements(value Value) []Value {
+	switch v := value.(type) {
+	case ListValue:
+		return v.Value
+	case ObjectValue:
+		keys := v.keys()
+		elements := make([]Value, len(keys))
+
+		for i, k := range keys {
+			elements[i] = v.Value[k]
+		}
+
+		return elements
+	default:
+		return nil
+	}
+}
+
+// values returns the values held by a MultiValue, or the value itself
+// otherwise.
+func values(value Value) []Value {
+	if multi, ok := value.(MultiValue); ok {
+		return multi.Value
+	}
+
+	return []Value{value}
+}
+
+// clampPosition converts a possibly negative position into an offset between 0
+// and length inclusive.
+func clampPosition(position, length int) int {
+	if position < 0 {
+		position += length
+	}
+
+	return max(0, min(position, length))
+}
@@ -0,0 +1,345 @@
+package evaluator
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+const (
+	ValueType_Undefined = iota
+	ValueType_Null
+	ValueType_Boolean
+	ValueType_Number
+	ValueType_String
+	ValueType_List
+	ValueType_Object
+	ValueType_Multi
+)
+
+var ValueTypeString map[int]string = map[int]string{
+	ValueType_Undefined: "Undefined",
+	ValueType_Null:      "Null",
+	ValueType_Boolean:   "Boolean",
+	ValueType_Number:    "Number",
+	ValueType_String:    "String",
+	ValueType_List:      "List",
+	ValueType_Object:    "Object",
+	ValueType_Multi:     "Multi",
+}
+
+// Value is the result of evaluating an fpath expression.
+type Value interface {
+	fmt.Stringer
+
+	Type() int
+	Native() any
+}
+
+func (NullValue) Type() int    { return ValueType_Null }
+func (BooleanValue) Type() int { return ValueType_Boolean }
+func (NumberValue) Type() int  { return ValueType_Number }
+func (StringValue) Type() int  { return ValueType_String }
+func (ListValue) Type() int    { return ValueType_List }
+func (ObjectValue) Type() int  { return ValueType_Object }
+func (MultiValue) Type() int   { return ValueType_Multi }
+
+// NullValue represents the absence of a value.
+type NullValue struct{}
+
+// String returns a string representation of a NullValue.
+func (NullValue) String() string {
+	return "NullValue{}"
+}
+
+// Native returns the value of a NullValue as nil.
+func (NullValue) Native() any {
+	return nil
+}
+
+// BooleanValue represents a boolean value.
+type BooleanValue struct {
+	Value bool
+}
+
+// String returns a string representation of a BooleanValue.
+func (b BooleanValue) String() string {
+	return fmt.Sprintf("BooleanValue{ Value: %t }", b.Value)
+}
+
+// Native returns the value of a BooleanValue as a bool.
+func (b BooleanValue) Native() any {
+	return b.Value
+}
+
+// NumberValue represents a number value.
+type NumberValue struct {
+	Value decimal.Decimal
+}
+
+// String returns a string representation of a NumberValue.
+func (n NumberValue) String() string {
+	return fmt.Sprintf("NumberValue{ Value: %s }", n.Value.String())
+}
+
+// Native returns the value of a NumberValue as a decimal.Decimal.
+func (n NumberValue) Native() any {
+	return n.Value
+}
+
+// StringValue represents a string value.
+type StringValue struct {
+	Value string
+}
+
+// String returns a string representation of a StringValue.
+func (s StringValue) String() string {
+	return fmt.Sprintf("StringValue{ Value: %q }", s.Value)
+}
+
+// Native returns the value of a StringValue as a string.
+func (s StringValue) Native() any {
+	return s.Value
+}
+
+// ListValue represents an ordered list of values.
+type ListValue struct {
+	Value []Value
+}
+
+// String returns a string representation of a ListValue.
+func (l ListValue) String() string {
+	valueStrings := make([]string, len(l.Value))
+
+	for i, v := range l.Value {
+		valueStrings[i] = v.String()
+	}
+
+	return fmt.Sprintf("ListValue{ Value: [%s] }", strings.Join(valueStrings, ", "))
+}
+
+// Native returns the value of a ListValue as a []any.
+func (l ListValue) Native() any {
+	native := make([]any, len(l.Value))
+
+	for i, v := range l.Value {
+		native[i] = v.Native()
+	}
+
+	return native
+}
+
+// ObjectValue represents a collection of values keyed by name.
+type ObjectValue struct {
+	Value map[string]Value
+}
+
+// String returns a string representation of an ObjectValue.
+// Keys are sorted so the representation is stable.
+func (o ObjectValue) String() string {
+	keys := o.keys()
+	valueStrings := make([]string, len(keys))
+
+	for i, k := range keys {
+		valueStrings[i] = fmt.Sprintf("%q: %s", k, o.Value[k].String())
+	}
+
+	return fmt.Sprintf("ObjectValue{ Value: {%s} }", strings.Join(valueStrings, ", "))
+}
+
+// Native returns the value of an ObjectValue as a map[string]any.
+func (o ObjectValue) Native() any {
+	native := make(map[string]any, len(o.Value))
+
+	for k, v := range o.Value {
+		native[k] = v.Native()
+	}
+
+	return native
+}
+
+// keys returns the keys of an ObjectValue in sorted order.
+func (o ObjectValue) keys() []string {
+	keys := make([]string, 0, len(o.Value))
+
+	for k := range o.Value {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+	return keys
+}
+
+// MultiValue represents the collection of values selected by a path that can
+// match more than one value, such as a wildcard or a recursive descent.
+// Unlike a ListValue, operations on a MultiValue apply to each of its values.
+type MultiValue struct {
+	Value []Value
+}
+
+// String returns a string representation of a MultiValue.
+func (m MultiValue) String() string {
+	valueStrings := make([]string, len(m.Value))
+
+	for i, v := range m.Value {
+		valueStrings[i] = v.String()
+	}
+
+	return fmt.Sprintf("MultiValue{ Value: [%s] }", strings.Join(valueStrings, ", "))
+}
+
+// Native returns the values of a MultiValue as a []any.
+func (m MultiValue) Native() any {
+	return ListValue{Value: m.Value}.Native()
+}
+
+// NewValue converts Go data into a Value.
+// Slices and arrays are converted to lists and maps with string keys are
+// converted to objects.
+// If the data is of a type that fpath doesn't support, or is a float that is
+// NaN or infinite, NewValue returns an error.
+func NewValue(data any) (value Value, err error) {
+	switch d := data.(type) {
+	case Value:
+		return d, nil
+	case nil:
+		return NullValue{}, nil
+	case bool:
+		return BooleanValue{Value: d}, nil
+	case int:
+		return NumberValue{Value: decimal.NewFromInt(int64(d))}, nil
+	case int8:
+		return NumberValue{Value: decimal.NewFromInt(int64(d))}, nil
+	case int16:
+		return NumberValue{Value: decimal.NewFromInt(int64(d))}, nil
+	case int32:
+		return NumberValue{Value: decimal.NewFromInt32(d)}, nil
+	case int64:
+		return NumberValue{Value: decimal.NewFromInt(d)}, nil
+	case uint:
+		return NumberValue{Value: decimal.NewFromUint64(uint64(d))}, nil
+	case uint8:
+		return NumberValue{Value: decimal.NewFromUint64(uint64(d))}, nil
+	case uint16:
+		return NumberValue{Value: decimal.NewFromUint64(uint64(d))}, nil
+	case uint32:
+		return NumberValue{Value: decimal.NewFromUint64(uint64(d))}, nil
+	case uint64:
+		return NumberValue{Value: decimal.NewFromUint64(d)}, nil
+	case float32:
+		return newFloatValue(float64(d), 32)
+	case float64:
+		return newFloatValue(d, 64)
+	case decimal.Decimal:
+		return NumberValue{Value: d}, nil
+	case json.Number:
+		return newNumberValue(string(d))
+	case string:
+		return StringValue{Value: d}, nil
+	case []any:
+		return newListValue(reflect.ValueOf(d))
+	case map[string]any:
+		return newObjectValue(reflect.ValueOf(d))
+	}
+
+	rv := reflect.ValueOf(data)
+
+	switch rv.Kind() {
+	case reflect.Pointer:
+		if rv.IsNil() {
+			return NullValue{}, nil
+		}
+
+		return NewValue(rv.Elem().Interface())
+	case reflect.Slice, reflect.Array:
+		if rv.Kind() == reflect.Slice && rv.IsNil() {
+			return NullValue{}, nil
+		}
+
+		return newListValue(rv)
+	case reflect.Map:
+		if rv.Type().Key().Kind() != reflect.String {
+			err = fmt.Errorf("unsupported map key type: %s", rv.Type().Key())
+			return
+		}
+
+		if rv.IsNil() {
+			return NullValue{}, nil
+		}
+
+		return newObjectValue(rv)
+	default:
+		err = fmt.Errorf("unsupported data type: %T", data)
+		return
+	}
+}
+
+// newNumberValue converts a string representation of a number into a
+// NumberValue.
+func newNumberValue(s string) (number NumberValue, err error) {
+	number.Value, err = decimal.NewFromString(s)
+
+	if err != nil {
+		err = fmt.Errorf("failed to convert %q to number: %w", s, err)
+		return
+	}
+
+	return number, nil
+}
+
+// newFloatValue converts a float of the given bit size into a NumberValue.
+// NaN and infinities have no decimal representation, so newFloatValue returns
+// an error for them.
+func newFloatValue(f float64, bitSize int) (number NumberValue, err error) {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		err = fmt.Errorf("unsupported number: %v", f)
+		return
+	}
+
+	if bitSize == 32 {
+		number.Value = decimal.NewFromFloat32(float32(f))
+		return number, nil
+	}
+
+	number.Value = decimal.NewFromFloat(f)
+	return number, nil
+}
+
+// newListValue converts each element of a slice or array into a Value.
+func newListValue(rv reflect.Value) (list ListValue, err error) {
+	list.Value = make([]Value, rv.Len())
+
+	for i := range list.Value {
+		list.Value[i], err = NewValue(rv.Index(i).Interface())
+
+		if err != nil {
+			err = fmt.Errorf("failed to convert element %d: %w", i, err)
+			return
+		}
+	}
+
+	return list, nil
+}
+
+// newObjectValue converts each entry of a map with string keys into a Value.
+func newObjectValue(rv reflect.Value) (object ObjectValue, err error) {
+	object.Value = make(map[string]Value, rv.Len())
+	iter := rv.MapRange()
+
+	for iter.Next() {
+		key := iter.Key().String()
+		object.Value[key], err = NewValue(iter.Value().Interface())
+
+		if err != nil {
+			err = fmt.Errorf("failed to convert key %q: %w", key, err)
+			return
+		}
+	}
+
+	return object, nil
+}
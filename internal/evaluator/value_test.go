@@ -0,0 +1,117 @@
+package evaluator
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/gkampitakis/go-snaps/snaps"
+	"github.com/shopspring/decimal"
+)
+
+func Test_NewValue(t *testing.T) {
+	testCases := map[string]struct {
+		data any
+	}{
+		"Boolean": {
+			data: true,
+		},
+		"Int": {
+			data: 123,
+		},
+		"Uint8": {
+			data: uint8(255),
+		},
+		"Float": {
+			data: 123.456,
+		},
+		"Decimal": {
+			data: decimal.RequireFromString("123.456"),
+		},
+		"Value": {
+			data: BooleanValue{Value: false},
+		},
+		"Nil": {
+			data: nil,
+		},
+		"String": {
+			data: "hello",
+		},
+		"JSON number": {
+			data: json.Number("1.5e3"),
+		},
+		"List": {
+			data: []any{1, "two", nil},
+		},
+		"Typed slice": {
+			data: []string{"a", "b"},
+		},
+		"Object": {
+			data: map[string]any{"b": 2, "a": []any{true}},
+		},
+		"Typed map": {
+			data: map[string]int{"a": 1},
+		},
+		"Pointer": {
+			data: &[]int{1},
+		},
+		"Nil slice": {
+			data: []int(nil),
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			value, err := NewValue(tc.data)
+
+			if err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+
+			snaps.MatchSnapshot(t, value.String())
+		})
+	}
+}
+
+func Test_NewValue_Error(t *testing.T) {
+	testCases := map[string]struct {
+		data any
+	}{
+		"Channel": {
+			data: make(chan int),
+		},
+		"Map key": {
+			data: map[int]any{1: 1},
+		},
+		"Nested": {
+			data: map[string]any{"a": []any{func() {}}},
+		},
+		"JSON number": {
+			data: json.Number("one"),
+		},
+		"NaN": {
+			data: math.NaN(),
+		},
+		"Infinity": {
+			data: math.Inf(1),
+		},
+		"Negative infinity": {
+			data: float32(math.Inf(-1)),
+		},
+		"Nested NaN": {
+			data: map[string]any{"a": math.NaN()},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			_, err := NewValue(tc.data)
+
+			if err == nil {
+				t.Fatalf("Expected error but none returned")
+			}
+
+			snaps.MatchSnapshot(t, err.Error())
+		})
+	}
+}
@@ -4,8 +4,11 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"slices"
+	"strconv"
 	"strings"
 	"unicode"
+	"unicode/utf16"
 )
 
 const (
@@ -20,6 +23,33 @@ const (
 	TokenType_Lesser
 	TokenType_OpenParan
 	TokenType_CloseParan
+	TokenType_Dot
+	TokenType_OpenBracket
+	TokenType_CloseBracket
+	TokenType_Colon
+	TokenType_DotDot
+	TokenType_Asterisk
+	TokenType_And
+	TokenType_Or
+	TokenType_Plus
+	TokenType_Minus
+	TokenType_Slash
+	TokenType_Percent
+	TokenType_GreaterEquals
+	TokenType_LesserEquals
+	TokenType_True
+	TokenType_False
+	TokenType_Null
+	TokenType_OpenBrace
+	TokenType_CloseBrace
+	TokenType_Comma
+	TokenType_In
+	TokenType_Between
+	TokenType_Matches
+	TokenType_StartsWith
+	TokenType_EndsWith
+	TokenType_Like
+	TokenType_Extract
 )
 
 var TokenTypeString map[int]string = map[int]string{
@@ -34,16 +64,103 @@ var TokenTypeString map[int]string = map[int]string{
 	TokenType_Lesser:        "Lesser",
 	TokenType_OpenParan:     "OpenParan",
 	TokenType_CloseParan:    "CloseParan",
+	TokenType_Dot:           "Dot",
+	TokenType_OpenBracket:   "OpenBracket",
+	TokenType_CloseBracket:  "CloseBracket",
+	TokenType_Colon:         "Colon",
+	TokenType_DotDot:        "DotDot",
+	TokenType_Asterisk:      "Asterisk",
+	TokenType_And:           "And",
+	TokenType_Or:            "Or",
+	TokenType_Plus:          "Plus",
+	TokenType_Minus:         "Minus",
+	TokenType_Slash:         "Slash",
+	TokenType_Percent:       "Percent",
+	TokenType_GreaterEquals: "GreaterEquals",
+	TokenType_LesserEquals:  "LesserEquals",
+	TokenType_True:          "True",
+	TokenType_False:         "False",
+	TokenType_Null:          "Null",
+	TokenType_OpenBrace:     "OpenBrace",
+	TokenType_CloseBrace:    "CloseBrace",
+	TokenType_Comma:         "Comma",
+	TokenType_In:            "In",
+	TokenType_Between:       "Between",
+	TokenType_Matches:       "Matches",
+	TokenType_StartsWith:    "StartsWith",
+	TokenType_EndsWith:      "EndsWith",
+	TokenType_Like:          "Like",
+	TokenType_Extract:       "Extract",
 }
 
 var UnexpectedEOF = errors.New("Unexpected EOF")
 
 var keywords = map[string]int{
-	"not":      TokenType_Not,
-	"equals":   TokenType_Equals,
-	"contains": TokenType_Contains,
-	"greater":  TokenType_Greater,
-	"lesser":   TokenType_Lesser,
+	"not":        TokenType_Not,
+	"equals":     TokenType_Equals,
+	"contains":   TokenType_Contains,
+	"greater":    TokenType_Greater,
+	"lesser":     TokenType_Lesser,
+	"and":        TokenType_And,
+	"or":         TokenType_Or,
+	"true":       TokenType_True,
+	"false":      TokenType_False,
+	"null":       TokenType_Null,
+	"in":         TokenType_In,
+	"between":    TokenType_Between,
+	"matches":    TokenType_Matches,
+	"startswith": TokenType_StartsWith,
+	"endswith":   TokenType_EndsWith,
+	"like":       TokenType_Like,
+	"extract":    TokenType_Extract,
+}
+
+// phrases maps keywords made up of more than one word to their token types.
+// Words in a phrase may be separated by any amount of whitespace.
+// Every spelling of the comparisons is included, so "greater than", "less than
+// or equal to" and "lesser or equals" are all recognised.
+var phrases = func() map[string]int {
+	phrases := map[string]int{
+		"equal to":    TokenType_Equals,
+		"at least":    TokenType_GreaterEquals,
+		"at most":     TokenType_LesserEquals,
+		"starts with": TokenType_StartsWith,
+		"ends with":   TokenType_EndsWith,
+	}
+
+	comparisons := map[string][2]int{
+		"greater": {TokenType_Greater, TokenType_GreaterEquals},
+		"lesser":  {TokenType_Lesser, TokenType_LesserEquals},
+		"less":    {TokenType_Lesser, TokenType_LesserEquals},
+	}
+
+	for comparison, tokenTypes := range comparisons {
+		phrases[comparison+" than"] = tokenTypes[0]
+
+		for _, than := range []string{"", " than"} {
+			for _, equals := range []string{"equals", "equal to"} {
+				phrases[comparison+than+" or "+equals] = tokenTypes[1]
+			}
+		}
+	}
+
+	return phrases
+}()
+
+// maxPhraseWords is the number of words in the longest phrase.
+var maxPhraseWords = func() (words int) {
+	for phrase := range phrases {
+		words = max(words, len(strings.Fields(phrase)))
+	}
+
+	return words
+}()
+
+// IsKeyword returns whether the token is a single keyword, such as contains or
+// equals, whose value is the keyword's text.
+func IsKeyword(tok Token) bool {
+	tokenType, ok := keywords[strings.ToLower(tok.Value)]
+	return ok && tokenType == tok.Type
 }
 
 // isLabelRune returns whether the provided rune is a valid label rune.
@@ -52,9 +169,12 @@ func isLabelRune(r rune) bool {
 	return unicode.IsLetter(r) || unicode.IsNumber(r) || r == '_'
 }
 
+// Token is a single unit of an fpath query.
+// Position is the offset of the token's first rune in the input.
 type Token struct {
-	Type  int
-	Value string
+	Type     int
+	Value    string
+	Position int
 }
 
 // NewLexer returns a new Lexer configured to read from a slice
@@ -71,6 +191,8 @@ type Lexer struct {
 	input []rune
 	index int
 	buf   *Token
+	last  int
+	end   int
 }
 
 // getRune returns the rune at the current index of the input and increments the
@@ -109,46 +231,201 @@ func (l *Lexer) GetToken() (tok Token, err error) {
 		return tok, nil
 	}
 
-	var r rune
-
 	for {
+		var r rune
 		r, err = l.peekRune()
 
 		if err != nil {
 			return tok, err
 		}
 
-		if unicode.IsSpace(r) {
+		if !unicode.IsSpace(r) {
+			break
+		}
+
+		l.index++
+	}
+
+	position := l.index
+	tok, err = l.readToken()
+	tok.Position = position
+	l.last = tok.Type
+	l.end = l.index
+	return tok, err
+}
+
+// endsOperand returns whether a token of the type can be the last token of an
+// operand, in which case a following sign is an arithmetic operator rather
+// than part of a number.
+func endsOperand(tokenType int) bool {
+	switch tokenType {
+	case TokenType_Number, TokenType_Label, TokenType_StringLiteral,
+		TokenType_True, TokenType_False, TokenType_Null,
+		TokenType_CloseParan, TokenType_CloseBracket, TokenType_CloseBrace:
+		return true
+	default:
+		return false
+	}
+}
+
+// readToken returns the token starting at the current index of the input.
+func (l *Lexer) readToken() (tok Token, err error) {
+	r, err := l.peekRune()
+
+	if err != nil {
+		return tok, err
+	}
+
+	if isDigit(r) {
+		return l.getTokenNumber()
+	}
+
+	if isLabelRune(r) {
+		return l.getTokenLabel()
+	}
+
+	switch r {
+	case '"', '\'':
+		l.index++
+		return l.getTokenStringLiteral(r)
+	case '`':
+		l.index++
+		return l.getTokenRawStringLiteral()
+	case '(':
+		l.index++
+		return Token{
+			Type: TokenType_OpenParan,
+		}, nil
+	case ')':
+		l.index++
+		return Token{
+			Type: TokenType_CloseParan,
+		}, nil
+	case '.':
+		l.index++
+
+		if r, _ = l.peekRune(); r == '.' {
 			l.index++
-			continue
+			return Token{
+				Type: TokenType_DotDot,
+			}, nil
 		}
 
-		if unicode.IsNumber(r) {
+		return Token{
+			Type: TokenType_Dot,
+		}, nil
+	case '[':
+		l.index++
+		return Token{
+			Type: TokenType_OpenBracket,
+		}, nil
+	case ']':
+		l.index++
+		return Token{
+			Type: TokenType_CloseBracket,
+		}, nil
+	case ':':
+		l.index++
+		return Token{
+			Type: TokenType_Colon,
+		}, nil
+	case '{':
+		l.index++
+		return Token{
+			Type: TokenType_OpenBrace,
+		}, nil
+	case '}':
+		l.index++
+		return Token{
+			Type: TokenType_CloseBrace,
+		}, nil
+	case ',':
+		l.index++
+		return Token{
+			Type: TokenType_Comma,
+		}, nil
+	case '*':
+		l.index++
+		return Token{
+			Type: TokenType_Asterisk,
+		}, nil
+	case '-', '+':
+		if !endsOperand(l.last) && l.index+1 < len(l.input) && isDigit(l.input[l.index+1]) {
 			return l.getTokenNumber()
 		}
 
-		if isLabelRune(r) {
-			return l.getTokenLabel()
+		l.index++
+
+		if r == '-' {
+			return Token{
+				Type: TokenType_Minus,
+			}, nil
 		}
 
-		switch r {
-		case '"':
+		return Token{
+			Type: TokenType_Plus,
+		}, nil
+	case '/':
+		l.index++
+		return Token{
+			Type: TokenType_Slash,
+		}, nil
+	case '%':
+		l.index++
+		return Token{
+			Type: TokenType_Percent,
+		}, nil
+	case '=':
+		l.index++
+
+		if r, _ = l.peekRune(); r == '=' {
 			l.index++
-			return l.getTokenStringLiteral()
-		case '(':
+		}
+
+		return Token{
+			Type: TokenType_Equals,
+		}, nil
+	case '!':
+		// != and !~ are read as not followed by equals or contains, which
+		// makes them the same as not equals and not contains.
+		l.index++
+		return Token{
+			Type: TokenType_Not,
+		}, nil
+	case '~':
+		l.index++
+		return Token{
+			Type: TokenType_Contains,
+		}, nil
+	case '>':
+		l.index++
+
+		if r, _ = l.peekRune(); r == '=' {
 			l.index++
 			return Token{
-				Type: TokenType_OpenParan,
+				Type: TokenType_GreaterEquals,
 			}, nil
-		case ')':
+		}
+
+		return Token{
+			Type: TokenType_Greater,
+		}, nil
+	case '<':
+		l.index++
+
+		if r, _ = l.peekRune(); r == '=' {
 			l.index++
 			return Token{
-				Type: TokenType_CloseParan,
+				Type: TokenType_LesserEquals,
 			}, nil
-		default:
-			err = fmt.Errorf("Invalid rune %q", r)
-			return
 		}
+
+		return Token{
+			Type: TokenType_Lesser,
+		}, nil
+	default:
+		err = fmt.Errorf("Invalid rune %q", r)
+		return
 	}
 }
 
@@ -163,43 +440,155 @@ func (l *Lexer) PeekToken() (tok Token, err error) {
 	}
 
 	tok, err = l.GetToken()
+
+	if err != nil {
+		return tok, err
+	}
+
 	l.buf = &tok
-	return tok, err
+	return tok, nil
 }
 
 // getTokenNumber returns the current number token in the input string.
+// Numbers may have a leading sign, a fractional part and an exponent, such as
+// -1.5e3, or may be hexadecimal or binary integers prefixed with 0x or 0b.
+// A sign directly after an operand is read as an arithmetic operator instead,
+// so "2-1" is a subtraction rather than 2 followed by -1.
+// Digits may be separated by underscores, such as 1_000_000.
+// The token's value is the number as written.
 // If there are no more tokens to process in the string, getToken returns an
 // io.EOF error.
 func (l *Lexer) getTokenNumber() (tok Token, err error) {
 	tok.Type = TokenType_Number
-	var r rune
+	start := l.index
 
-	for {
-		r, err = l.peekRune()
+	if r, _ := l.peekRune(); r == '-' || r == '+' {
+		l.index++
+	}
 
-		if err == io.EOF {
-			return tok, nil
+	if l.peekRadixPrefix('x') {
+		l.index += 2
+
+		if err = l.getDigits(isHexDigit); err != nil {
+			return
 		}
 
-		if err != nil {
-			return tok, err
+		tok.Value = string(l.input[start:l.index])
+		return tok, nil
+	}
+
+	if l.peekRadixPrefix('b') {
+		l.index += 2
+
+		if err = l.getDigits(isBinaryDigit); err != nil {
+			return
+		}
+
+		tok.Value = string(l.input[start:l.index])
+		return tok, nil
+	}
+
+	if err = l.getDigits(isDigit); err != nil {
+		return
+	}
+
+	if l.index+1 < len(l.input) && l.input[l.index] == '.' && isDigit(l.input[l.index+1]) {
+		l.index++
+
+		if err = l.getDigits(isDigit); err != nil {
+			return
 		}
+	}
+
+	if r, _ := l.peekRune(); r == 'e' || r == 'E' {
+		exponent := l.index + 1
+
+		if exponent < len(l.input) && (l.input[exponent] == '-' || l.input[exponent] == '+') {
+			exponent++
+		}
+
+		if exponent < len(l.input) && isDigit(l.input[exponent]) {
+			l.index = exponent
+
+			if err = l.getDigits(isDigit); err != nil {
+				return
+			}
+		}
+	}
+
+	tok.Value = string(l.input[start:l.index])
+	return tok, nil
+}
+
+// getDigits consumes a run of digits that may be separated by single
+// underscores.
+// If there are no digits, or an underscore isn't between two digits,
+// getDigits returns an error.
+func (l *Lexer) getDigits(isValid func(rune) bool) (err error) {
+	start := l.index
+
+	for l.index < len(l.input) {
+		r := l.input[l.index]
+
+		if r == '_' {
+			if l.index == start || l.index+1 == len(l.input) || !isValid(l.input[l.index+1]) {
+				err = fmt.Errorf("invalid digit separator at position %d", l.index)
+				return
+			}
 
-		if unicode.IsNumber(r) {
 			l.index++
-			tok.Value += string(r)
 			continue
 		}
 
-		return tok, nil
+		if !isValid(r) {
+			break
+		}
+
+		l.index++
 	}
+
+	if l.index == start {
+		err = fmt.Errorf("expected digit at position %d", l.index)
+		return
+	}
+
+	return nil
+}
+
+// peekRadixPrefix returns whether the input at the current index starts with a
+// zero followed by the radix rune in either case, such as 0x or 0X.
+func (l *Lexer) peekRadixPrefix(radix rune) bool {
+	return l.index+1 < len(l.input) &&
+		l.input[l.index] == '0' &&
+		unicode.ToLower(l.input[l.index+1]) == radix
+}
+
+// isDigit returns whether the rune is a decimal digit.
+func isDigit(r rune) bool {
+	return r >= '0' && r <= '9'
+}
+
+// isHexDigit returns whether the rune is a hexadecimal digit.
+func isHexDigit(r rune) bool {
+	return isDigit(r) || (r >= 'a' && r <= 'f') || (r >= 'A' && r <= 'F')
+}
+
+// isBinaryDigit returns whether the rune is a binary digit.
+func isBinaryDigit(r rune) bool {
+	return r == '0' || r == '1'
 }
 
 // getTokenLabel returns the current label token in the input string.
+// Labels spelled like a keyword, or starting a keyword phrase such as
+// "greater than", are returned as that keyword instead, with the keyword's text
+// as the token's value. A label directly after a dot, such as the contains in
+// order.contains, is always a label since it names a field.
 // If there are no more tokens to process in the string, getToken returns an
 // io.EOF error.
 func (l *Lexer) getTokenLabel() (tok Token, err error) {
 	tok.Type = TokenType_Label
+	start := l.index
+	field := (l.last == TokenType_Dot || l.last == TokenType_DotDot) && start == l.end
 	var r rune
 
 	for {
@@ -218,9 +607,22 @@ func (l *Lexer) getTokenLabel() (tok Token, err error) {
 		break
 	}
 
+	if field {
+		return tok, nil
+	}
+
+	if key, end, ok := l.matchPhrase(tok.Value); ok {
+		l.index = end
+		return Token{
+			Type:  key,
+			Value: string(l.input[start:end]),
+		}, nil
+	}
+
 	if key, ok := keywords[strings.ToLower(tok.Value)]; ok {
 		return Token{
-			Type: key,
+			Type:  key,
+			Value: tok.Value,
 		}, nil
 	}
 
@@ -231,12 +633,51 @@ func (l *Lexer) getTokenLabel() (tok Token, err error) {
 	return tok, err
 }
 
+// matchPhrase returns the token type of the longest phrase made up of the word
+// that has just been read and the words that follow it, along with the index
+// the phrase ends at.
+// If no phrase matches, matchPhrase returns false and the index is unchanged.
+func (l *Lexer) matchPhrase(word string) (tokenType, end int, ok bool) {
+	words := []string{strings.ToLower(word)}
+	index := l.index
+
+	for len(words) < maxPhraseWords {
+		start := index
+
+		for start < len(l.input) && unicode.IsSpace(l.input[start]) {
+			start++
+		}
+
+		next := start
+
+		for next < len(l.input) && isLabelRune(l.input[next]) {
+			next++
+		}
+
+		if next == start {
+			break
+		}
+
+		words = append(words, strings.ToLower(string(l.input[start:next])))
+		index = next
+
+		if key, found := phrases[strings.Join(words, " ")]; found {
+			tokenType, end, ok = key, index, true
+		}
+	}
+
+	return tokenType, end, ok
+}
+
 // getTokenStringLiteral returns the current string literal token in the input
-// string.
+// string, which is terminated by the quote rune.
+// Backslashes begin escape sequences, which may be one of \", \', \\, \/, \n,
+// \r, \t or \uXXXX. Any other escape sequence returns an error.
 // If the token reaches the end of the string, getTokenStringLiteral returns an
 // UnexpectedEOF error.
-func (l *Lexer) getTokenStringLiteral() (tok Token, err error) {
+func (l *Lexer) getTokenStringLiteral(quote rune) (tok Token, err error) {
 	tok.Type = TokenType_StringLiteral
+	var value strings.Builder
 	var r rune
 
 	for {
@@ -252,12 +693,119 @@ func (l *Lexer) getTokenStringLiteral() (tok Token, err error) {
 			return
 		}
 
-		if r == '"' {
+		if r == quote {
 			break
 		}
 
-		tok.Value += string(r)
+		if r == '\\' {
+			r, err = l.getEscape()
+
+			if err != nil {
+				return
+			}
+		}
+
+		value.WriteRune(r)
+	}
+
+	tok.Value = value.String()
+	return tok, nil
+}
+
+// getEscape returns the rune represented by the escape sequence following a
+// backslash.
+func (l *Lexer) getEscape() (r rune, err error) {
+	position := l.index - 1
+	r, err = l.getRune()
+
+	if err == io.EOF {
+		err = UnexpectedEOF
+		return
+	}
+
+	switch r {
+	case '"', '\'', '\\', '/':
+		return r, nil
+	case 'n':
+		return '\n', nil
+	case 'r':
+		return '\r', nil
+	case 't':
+		return '\t', nil
+	case 'u':
+		r, err = l.getUnicodeEscape(position)
+
+		if err != nil {
+			return
+		}
+
+		if !utf16.IsSurrogate(r) {
+			return r, nil
+		}
+
+		// A high surrogate must be followed by an escaped low surrogate to
+		// make up a rune outside the basic multilingual plane.
+		next := l.index
+
+		if l.index+1 < len(l.input) && l.input[l.index] == '\\' && l.input[l.index+1] == 'u' {
+			l.index += 2
+			var low rune
+			low, err = l.getUnicodeEscape(next)
+
+			if err != nil {
+				return
+			}
+
+			if combined := utf16.DecodeRune(r, low); combined != unicode.ReplacementChar {
+				return combined, nil
+			}
+		}
+
+		err = fmt.Errorf("invalid surrogate pair in escape sequence at position %d", position)
+		return
+	default:
+		err = fmt.Errorf("invalid escape sequence '\\%c' at position %d (use a backtick raw string for text with backslashes, such as regular expressions)", r, position)
+		return
+	}
+}
+
+// getUnicodeEscape returns the rune represented by the four hexadecimal digits
+// of a \uXXXX escape sequence beginning at position.
+func (l *Lexer) getUnicodeEscape(position int) (r rune, err error) {
+	if l.index+4 > len(l.input) {
+		err = UnexpectedEOF
+		return
+	}
+
+	digits := string(l.input[l.index : l.index+4])
+	value, err := strconv.ParseUint(digits, 16, 16)
+
+	if err != nil {
+		err = fmt.Errorf("invalid unicode escape sequence '\\u%s' at position %d", digits, position)
+		return
+	}
+
+	l.index += 4
+	return rune(value), nil
+}
+
+// getTokenRawStringLiteral returns the current raw string literal token in the
+// input string, which is terminated by a backtick.
+// Raw string literals have no escape sequences, which makes them convenient for
+// regular expressions.
+// If the token reaches the end of the string, getTokenRawStringLiteral returns
+// an UnexpectedEOF error.
+func (l *Lexer) getTokenRawStringLiteral() (tok Token, err error) {
+	tok.Type = TokenType_StringLiteral
+	end := slices.Index(l.input[l.index:], '`')
+
+	if end == -1 {
+		l.index = len(l.input)
+		err = UnexpectedEOF
+		return
 	}
 
+	tok.Value = string(l.input[l.index : l.index+end])
+	l.index += end + 1
 	return tok, nil
 }
@@ -25,7 +25,7 @@ func _tokensMatch(expected, actual Token) (err error) {
 		err = fmt.Errorf("Unexpected value\nExpected: %s\nActual: %s", expected.Value, actual.Value)
 	}
 
-	return nil
+	return err
 }
 
 func Test_isLabelRune(t *testing.T) {
@@ -170,6 +170,222 @@ func Test_Lexer_getToken(t *testing.T) {
 				{Type: TokenType_Number, Value: "123"},
 			},
 		},
+		"Negative number": {
+			input: "-123",
+			expectedTokens: []Token{
+				{Type: TokenType_Number, Value: "-123"},
+			},
+		},
+		"Decimal": {
+			input: "123.456",
+			expectedTokens: []Token{
+				{Type: TokenType_Number, Value: "123.456"},
+			},
+		},
+		"Signed decimals": {
+			input: "-1.5 equals +2.25",
+			expectedTokens: []Token{
+				{Type: TokenType_Number, Value: "-1.5"},
+				{Type: TokenType_Equals, Value: "equals"},
+				{Type: TokenType_Number, Value: "+2.25"},
+			},
+		},
+		"Exponent": {
+			input: "1.5e3 2E-2 3e+1",
+			expectedTokens: []Token{
+				{Type: TokenType_Number, Value: "1.5e3"},
+				{Type: TokenType_Number, Value: "2E-2"},
+				{Type: TokenType_Number, Value: "3e+1"},
+			},
+		},
+		"Exponent without digits": {
+			input: "1e",
+			expectedTokens: []Token{
+				{Type: TokenType_Number, Value: "1"},
+				{Type: TokenType_Label, Value: "e"},
+			},
+		},
+		"Digit separators": {
+			input: "1_000_000.000_1",
+			expectedTokens: []Token{
+				{Type: TokenType_Number, Value: "1_000_000.000_1"},
+			},
+		},
+		"Hexadecimal": {
+			input: "0xFF equals -0x1_0",
+			expectedTokens: []Token{
+				{Type: TokenType_Number, Value: "0xFF"},
+				{Type: TokenType_Equals, Value: "equals"},
+				{Type: TokenType_Number, Value: "-0x1_0"},
+			},
+		},
+		"Binary": {
+			input: "0b1010",
+			expectedTokens: []Token{
+				{Type: TokenType_Number, Value: "0b1010"},
+			},
+		},
+		"Number path": {
+			input: "0.sku",
+			expectedTokens: []Token{
+				{Type: TokenType_Number, Value: "0"},
+				{Type: TokenType_Dot},
+				{Type: TokenType_Label, Value: "sku"},
+			},
+		},
+		"Arithmetic": {
+			input: "a + b - c * d / e % f",
+			expectedTokens: []Token{
+				{Type: TokenType_Label, Value: "a"},
+				{Type: TokenType_Plus},
+				{Type: TokenType_Label, Value: "b"},
+				{Type: TokenType_Minus},
+				{Type: TokenType_Label, Value: "c"},
+				{Type: TokenType_Asterisk},
+				{Type: TokenType_Label, Value: "d"},
+				{Type: TokenType_Slash},
+				{Type: TokenType_Label, Value: "e"},
+				{Type: TokenType_Percent},
+				{Type: TokenType_Label, Value: "f"},
+			},
+		},
+		"Sign after operand": {
+			input: "2-1 (a)+1 b[0]-1",
+			expectedTokens: []Token{
+				{Type: TokenType_Number, Value: "2"},
+				{Type: TokenType_Minus},
+				{Type: TokenType_Number, Value: "1"},
+				{Type: TokenType_OpenParan},
+				{Type: TokenType_Label, Value: "a"},
+				{Type: TokenType_CloseParan},
+				{Type: TokenType_Plus},
+				{Type: TokenType_Number, Value: "1"},
+				{Type: TokenType_Label, Value: "b"},
+				{Type: TokenType_OpenBracket},
+				{Type: TokenType_Number, Value: "0"},
+				{Type: TokenType_CloseBracket},
+				{Type: TokenType_Minus},
+				{Type: TokenType_Number, Value: "1"},
+			},
+		},
+		"Sign after operator": {
+			input: "2 - -1 * +3",
+			expectedTokens: []Token{
+				{Type: TokenType_Number, Value: "2"},
+				{Type: TokenType_Minus},
+				{Type: TokenType_Number, Value: "-1"},
+				{Type: TokenType_Asterisk},
+				{Type: TokenType_Number, Value: "+3"},
+			},
+		},
+		"Minus without digit": {
+			input: "- 1",
+			expectedTokens: []Token{
+				{Type: TokenType_Minus},
+				{Type: TokenType_Number, Value: "1"},
+			},
+		},
+		"Symbols": {
+			input: "= == != ~ !~ > < >= <=",
+			expectedTokens: []Token{
+				{Type: TokenType_Equals},
+				{Type: TokenType_Equals},
+				{Type: TokenType_Not},
+				{Type: TokenType_Equals},
+				{Type: TokenType_Contains},
+				{Type: TokenType_Not},
+				{Type: TokenType_Contains},
+				{Type: TokenType_Greater},
+				{Type: TokenType_Lesser},
+				{Type: TokenType_GreaterEquals},
+				{Type: TokenType_LesserEquals},
+			},
+		},
+		"Symbols without spaces": {
+			input: "a>=-1",
+			expectedTokens: []Token{
+				{Type: TokenType_Label, Value: "a"},
+				{Type: TokenType_GreaterEquals},
+				{Type: TokenType_Number, Value: "-1"},
+			},
+		},
+		"Phrases": {
+			input: "greater than lesser than less than at least at most equal to",
+			expectedTokens: []Token{
+				{Type: TokenType_Greater, Value: "greater than"},
+				{Type: TokenType_Lesser, Value: "lesser than"},
+				{Type: TokenType_Lesser, Value: "less than"},
+				{Type: TokenType_GreaterEquals, Value: "at least"},
+				{Type: TokenType_LesserEquals, Value: "at most"},
+				{Type: TokenType_Equals, Value: "equal to"},
+			},
+		},
+		"Inclusive phrases": {
+			input: "greater or equals GREATER THAN OR EQUAL TO lesser  than\tor equals less than or equal to",
+			expectedTokens: []Token{
+				{Type: TokenType_GreaterEquals, Value: "greater or equals"},
+				{Type: TokenType_GreaterEquals, Value: "GREATER THAN OR EQUAL TO"},
+				{Type: TokenType_LesserEquals, Value: "lesser  than\tor equals"},
+				{Type: TokenType_LesserEquals, Value: "less than or equal to"},
+			},
+		},
+		"Phrase prefix": {
+			input: "greater than or 1",
+			expectedTokens: []Token{
+				{Type: TokenType_Greater, Value: "greater than"},
+				{Type: TokenType_Or, Value: "or"},
+				{Type: TokenType_Number, Value: "1"},
+			},
+		},
+		"Phrase words as labels": {
+			input: "at less than_x",
+			expectedTokens: []Token{
+				{Type: TokenType_Label, Value: "at"},
+				{Type: TokenType_Label, Value: "less"},
+				{Type: TokenType_Label, Value: "than_x"},
+			},
+		},
+		"Literals": {
+			input: "true FALSE Null",
+			expectedTokens: []Token{
+				{Type: TokenType_True, Value: "true"},
+				{Type: TokenType_False, Value: "FALSE"},
+				{Type: TokenType_Null, Value: "Null"},
+			},
+		},
+		"Collections": {
+			input: `{"a": [1, 2]}`,
+			expectedTokens: []Token{
+				{Type: TokenType_OpenBrace},
+				{Type: TokenType_StringLiteral, Value: "a"},
+				{Type: TokenType_Colon},
+				{Type: TokenType_OpenBracket},
+				{Type: TokenType_Number, Value: "1"},
+				{Type: TokenType_Comma},
+				{Type: TokenType_Number, Value: "2"},
+				{Type: TokenType_CloseBracket},
+				{Type: TokenType_CloseBrace},
+			},
+		},
+		"Membership": {
+			input: "IN between",
+			expectedTokens: []Token{
+				{Type: TokenType_In, Value: "IN"},
+				{Type: TokenType_Between, Value: "between"},
+			},
+		},
+		"Patterns": {
+			input: "matches startsWith starts with endswith ends  with like extract",
+			expectedTokens: []Token{
+				{Type: TokenType_Matches, Value: "matches"},
+				{Type: TokenType_StartsWith, Value: "startsWith"},
+				{Type: TokenType_StartsWith, Value: "starts with"},
+				{Type: TokenType_EndsWith, Value: "endswith"},
+				{Type: TokenType_EndsWith, Value: "ends  with"},
+				{Type: TokenType_Like, Value: "like"},
+				{Type: TokenType_Extract, Value: "extract"},
+			},
+		},
 		"Label": {
 			input: "fletcher",
 			expectedTokens: []Token{
@@ -182,34 +398,78 @@ func Test_Lexer_getToken(t *testing.T) {
 				{Type: TokenType_StringLiteral, Value: "hello world"},
 			},
 		},
+		"StringLiteral escapes": {
+			input: `"a \"b\" \\ \/ \n\r\t"`,
+			expectedTokens: []Token{
+				{Type: TokenType_StringLiteral, Value: "a \"b\" \\ / \n\r\t"},
+			},
+		},
+		"StringLiteral unicode escape": {
+			input: `"caf\u00e9 \uD83D\uDE00"`,
+			expectedTokens: []Token{
+				{Type: TokenType_StringLiteral, Value: "café 😀"},
+			},
+		},
+		"StringLiteral single quotes": {
+			input: `'it\'s "quoted"'`,
+			expectedTokens: []Token{
+				{Type: TokenType_StringLiteral, Value: `it's "quoted"`},
+			},
+		},
+		"StringLiteral raw": {
+			input: "`^/api/v[0-9]+\\d`",
+			expectedTokens: []Token{
+				{Type: TokenType_StringLiteral, Value: `^/api/v[0-9]+\d`},
+			},
+		},
+		"StringLiteral empty": {
+			input: `"" '' ` + "``",
+			expectedTokens: []Token{
+				{Type: TokenType_StringLiteral},
+				{Type: TokenType_StringLiteral},
+				{Type: TokenType_StringLiteral},
+			},
+		},
 		"Keyword Not": {
 			input: "not",
 			expectedTokens: []Token{
-				{Type: TokenType_Not},
+				{Type: TokenType_Not, Value: "not"},
 			},
 		},
 		"Keyword Equals": {
 			input: "equals",
 			expectedTokens: []Token{
-				{Type: TokenType_Equals},
+				{Type: TokenType_Equals, Value: "equals"},
 			},
 		},
 		"Keyword Contains": {
 			input: "contains",
 			expectedTokens: []Token{
-				{Type: TokenType_Contains},
+				{Type: TokenType_Contains, Value: "contains"},
 			},
 		},
 		"Keyword Greater": {
 			input: "greater",
 			expectedTokens: []Token{
-				{Type: TokenType_Greater},
+				{Type: TokenType_Greater, Value: "greater"},
 			},
 		},
 		"Keyword Lesser": {
 			input: "lesser",
 			expectedTokens: []Token{
-				{Type: TokenType_Lesser},
+				{Type: TokenType_Lesser, Value: "lesser"},
+			},
+		},
+		"Keyword And": {
+			input: "and",
+			expectedTokens: []Token{
+				{Type: TokenType_And, Value: "and"},
+			},
+		},
+		"Keyword Or": {
+			input: "OR",
+			expectedTokens: []Token{
+				{Type: TokenType_Or, Value: "OR"},
 			},
 		},
 		"OpenParan": {
@@ -224,6 +484,84 @@ func Test_Lexer_getToken(t *testing.T) {
 				{Type: TokenType_CloseParan},
 			},
 		},
+		"Dot": {
+			input: ".",
+			expectedTokens: []Token{
+				{Type: TokenType_Dot},
+			},
+		},
+		"Brackets": {
+			input: "[]",
+			expectedTokens: []Token{
+				{Type: TokenType_OpenBracket},
+				{Type: TokenType_CloseBracket},
+			},
+		},
+		"Slice": {
+			input: "items[1:-1]",
+			expectedTokens: []Token{
+				{Type: TokenType_Label, Value: "items"},
+				{Type: TokenType_OpenBracket},
+				{Type: TokenType_Number, Value: "1"},
+				{Type: TokenType_Colon},
+				{Type: TokenType_Number, Value: "-1"},
+				{Type: TokenType_CloseBracket},
+			},
+		},
+		"Wildcard": {
+			input: "items[*]",
+			expectedTokens: []Token{
+				{Type: TokenType_Label, Value: "items"},
+				{Type: TokenType_OpenBracket},
+				{Type: TokenType_Asterisk},
+				{Type: TokenType_CloseBracket},
+			},
+		},
+		"Recursive": {
+			input: "order..id",
+			expectedTokens: []Token{
+				{Type: TokenType_Label, Value: "order"},
+				{Type: TokenType_DotDot},
+				{Type: TokenType_Label, Value: "id"},
+			},
+		},
+		"Keyword field": {
+			input: "order.contains contains ..not",
+			expectedTokens: []Token{
+				{Type: TokenType_Label, Value: "order"},
+				{Type: TokenType_Dot},
+				{Type: TokenType_Label, Value: "contains"},
+				{Type: TokenType_Contains, Value: "contains"},
+				{Type: TokenType_DotDot},
+				{Type: TokenType_Label, Value: "not"},
+			},
+		},
+		"Phrase field": {
+			input: "a.greater than",
+			expectedTokens: []Token{
+				{Type: TokenType_Label, Value: "a"},
+				{Type: TokenType_Dot},
+				{Type: TokenType_Label, Value: "greater"},
+				{Type: TokenType_Label, Value: "than"},
+			},
+		},
+		"Keyword after spaced dot": {
+			input: ". equals",
+			expectedTokens: []Token{
+				{Type: TokenType_Dot},
+				{Type: TokenType_Equals, Value: "equals"},
+			},
+		},
+		"Path": {
+			input: "order.customer.email",
+			expectedTokens: []Token{
+				{Type: TokenType_Label, Value: "order"},
+				{Type: TokenType_Dot},
+				{Type: TokenType_Label, Value: "customer"},
+				{Type: TokenType_Dot},
+				{Type: TokenType_Label, Value: "email"},
+			},
+		},
 	}
 
 	for name, tc := range testCases {
@@ -245,6 +583,125 @@ func Test_Lexer_getToken(t *testing.T) {
 	}
 }
 
+func Test_Lexer_getToken_Phrases(t *testing.T) {
+	testCases := map[string]struct {
+		phrase   string
+		expected int
+	}{
+		"greater than": {
+			phrase:   "greater than",
+			expected: TokenType_Greater,
+		},
+		"greater or equals": {
+			phrase:   "greater or equals",
+			expected: TokenType_GreaterEquals,
+		},
+		"greater or equal to": {
+			phrase:   "greater or equal to",
+			expected: TokenType_GreaterEquals,
+		},
+		"greater than or equals": {
+			phrase:   "greater than or equals",
+			expected: TokenType_GreaterEquals,
+		},
+		"greater than or equal to": {
+			phrase:   "greater than or equal to",
+			expected: TokenType_GreaterEquals,
+		},
+		"lesser than": {
+			phrase:   "lesser than",
+			expected: TokenType_Lesser,
+		},
+		"lesser or equals": {
+			phrase:   "lesser or equals",
+			expected: TokenType_LesserEquals,
+		},
+		"lesser or equal to": {
+			phrase:   "lesser or equal to",
+			expected: TokenType_LesserEquals,
+		},
+		"lesser than or equals": {
+			phrase:   "lesser than or equals",
+			expected: TokenType_LesserEquals,
+		},
+		"lesser than or equal to": {
+			phrase:   "lesser than or equal to",
+			expected: TokenType_LesserEquals,
+		},
+		"less than": {
+			phrase:   "less than",
+			expected: TokenType_Lesser,
+		},
+		"less or equals": {
+			phrase:   "less or equals",
+			expected: TokenType_LesserEquals,
+		},
+		"less or equal to": {
+			phrase:   "less or equal to",
+			expected: TokenType_LesserEquals,
+		},
+		"less than or equals": {
+			phrase:   "less than or equals",
+			expected: TokenType_LesserEquals,
+		},
+		"less than or equal to": {
+			phrase:   "less than or equal to",
+			expected: TokenType_LesserEquals,
+		},
+		"equal to": {
+			phrase:   "equal to",
+			expected: TokenType_Equals,
+		},
+		"at least": {
+			phrase:   "at least",
+			expected: TokenType_GreaterEquals,
+		},
+		"at most": {
+			phrase:   "at most",
+			expected: TokenType_LesserEquals,
+		},
+		"starts with": {
+			phrase:   "starts with",
+			expected: TokenType_StartsWith,
+		},
+		"ends with": {
+			phrase:   "ends with",
+			expected: TokenType_EndsWith,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			lexer := NewLexer("a " + tc.phrase + " 1")
+			expectedTokens := []Token{
+				{Type: TokenType_Label, Value: "a"},
+				{Type: tc.expected, Value: tc.phrase},
+				{Type: TokenType_Number, Value: "1"},
+			}
+
+			for _, expected := range expectedTokens {
+				tok, err := lexer.GetToken()
+
+				if err != nil {
+					t.Fatalf("Unexpected error: %s", err)
+				}
+
+				if err := _tokensMatch(expected, tok); err != nil {
+					t.Fatalf("Unexpected result: %s", err)
+				}
+			}
+
+			if _, err := lexer.GetToken(); err != io.EOF {
+				t.Fatalf("Expected EOF but got %v", err)
+			}
+		})
+	}
+
+	if len(testCases) != len(phrases) {
+		t.Fatalf("Expected a test case for each of the %d phrases but got %d", len(phrases), len(testCases))
+	}
+}
+
 func Test_Lexer_getToken_EOF(t *testing.T) {
 	input := "  123  "
 	expected := Token{
@@ -271,7 +728,7 @@ func Test_Lexer_getToken_EOF(t *testing.T) {
 }
 
 func Test_Lexer_getToken_InvalidRune(t *testing.T) {
-	input := "  123  `"
+	input := "  123  $"
 	expected := Token{
 		Type:  TokenType_Number,
 		Value: "123",
@@ -297,6 +754,41 @@ func Test_Lexer_getToken_InvalidRune(t *testing.T) {
 	snaps.MatchSnapshot(t, err.Error())
 }
 
+func Test_Lexer_getTokenNumber_Error(t *testing.T) {
+	testCases := map[string]struct {
+		input string
+	}{
+		"Double separator": {
+			input: "1__000",
+		},
+		"Trailing separator": {
+			input: "1_",
+		},
+		"Separator before point": {
+			input: "1_.5",
+		},
+		"Hexadecimal without digits": {
+			input: "0x",
+		},
+		"Binary invalid digit": {
+			input: "0b2",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			lexer := NewLexer(tc.input)
+			_, err := lexer.GetToken()
+
+			if err == nil {
+				t.Fatalf("Error expected but not returned")
+			}
+
+			snaps.MatchSnapshot(t, err.Error())
+		})
+	}
+}
+
 func Test_Lexer_peekToken(t *testing.T) {
 	input := "123 equals"
 	firstExpected := Token{
@@ -304,7 +796,8 @@ func Test_Lexer_peekToken(t *testing.T) {
 		Value: "123",
 	}
 	secondExpected := Token{
-		Type: TokenType_Equals,
+		Type:  TokenType_Equals,
+		Value: "equals",
 	}
 	lexer := NewLexer(input)
 	shouldBreak := false
@@ -374,6 +867,87 @@ func Test_Lexer_peekToken(t *testing.T) {
 	})
 }
 
+func Test_Lexer_peekToken_EOF(t *testing.T) {
+	input := "123"
+	lexer := NewLexer(input)
+
+	if _, err := lexer.GetToken(); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	for i := 0; i < 100; i++ {
+		if _, err := lexer.PeekToken(); err != io.EOF {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+	}
+
+	if _, err := lexer.GetToken(); err != io.EOF {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+}
+
+func Test_Lexer_getToken_Position(t *testing.T) {
+	input := ` name equals "bob" `
+	expectedPositions := []int{1, 6, 13}
+	lexer := NewLexer(input)
+
+	for _, expected := range expectedPositions {
+		tok, err := lexer.GetToken()
+
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+
+		if tok.Position != expected {
+			t.Fatalf("Unexpected position\nExpected: %d\nActual: %d", expected, tok.Position)
+		}
+	}
+}
+
+func Test_Lexer_getTokenStringLiteral_Error(t *testing.T) {
+	testCases := map[string]struct {
+		input string
+	}{
+		"Invalid escape": {
+			input: `x equals "a\qb"`,
+		},
+		"Invalid unicode escape": {
+			input: `"\u00zz"`,
+		},
+		"Short unicode escape": {
+			input: `"\u00"`,
+		},
+		"Lone surrogate": {
+			input: `"\uD83D"`,
+		},
+		"Invalid surrogate pair": {
+			input: `"\uD83D\u0041"`,
+		},
+		"Escape EOF": {
+			input: `"\`,
+		},
+		"Single quote EOF": {
+			input: `'hello"`,
+		},
+		"Raw EOF": {
+			input: "`hello",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			lexer := NewLexer(tc.input)
+			var err error
+
+			for err == nil {
+				_, err = lexer.GetToken()
+			}
+
+			snaps.MatchSnapshot(t, err.Error())
+		})
+	}
+}
+
 func Test_Lexer_getTokenStringLiteral_UnexpectedEOF(t *testing.T) {
 	input := `"hello `
 	lexer := NewLexer(input)
@@ -2,6 +2,8 @@ package parser
 
 import (
 	"fmt"
+	"regexp"
+	"slices"
 	"strings"
 
 	"github.com/shopspring/decimal"
@@ -12,13 +14,85 @@ const (
 	NodeType_Block
 	NodeType_Number
 	NodeType_Equals
+	NodeType_Label
+	NodeType_Field
+	NodeType_Index
+	NodeType_Slice
+	NodeType_Wildcard
+	NodeType_Recursive
+	NodeType_Context
+	NodeType_Filter
+	NodeType_Contains
+	NodeType_Greater
+	NodeType_Lesser
+	NodeType_Not
+	NodeType_NotOperation
+	NodeType_And
+	NodeType_Or
+	NodeType_String
+	NodeType_Add
+	NodeType_Subtract
+	NodeType_Multiply
+	NodeType_Divide
+	NodeType_Modulo
+	NodeType_Negate
+	NodeType_GreaterEquals
+	NodeType_LesserEquals
+	NodeType_Boolean
+	NodeType_Null
+	NodeType_List
+	NodeType_Object
+	NodeType_In
+	NodeType_Between
+	NodeType_Matches
+	NodeType_StartsWith
+	NodeType_EndsWith
+	NodeType_Like
+	NodeType_Extract
+	NodeType_Call
 )
 
 var NodeTypeString map[int]string = map[int]string{
-	NodeType_Undefined: "Undefined",
-	NodeType_Block:     "Block",
-	NodeType_Number:    "Number",
-	NodeType_Equals:    "Equals",
+	NodeType_Undefined:     "Undefined",
+	NodeType_Block:         "Block",
+	NodeType_Number:        "Number",
+	NodeType_Equals:        "Equals",
+	NodeType_Label:         "Label",
+	NodeType_Field:         "Field",
+	NodeType_Index:         "Index",
+	NodeType_Slice:         "Slice",
+	NodeType_Wildcard:      "Wildcard",
+	NodeType_Recursive:     "Recursive",
+	NodeType_Context:       "Context",
+	NodeType_Filter:        "Filter",
+	NodeType_Contains:      "Contains",
+	NodeType_Greater:       "Greater",
+	NodeType_Lesser:        "Lesser",
+	NodeType_Not:           "Not",
+	NodeType_NotOperation:  "NotOperation",
+	NodeType_And:           "And",
+	NodeType_Or:            "Or",
+	NodeType_String:        "String",
+	NodeType_Add:           "Add",
+	NodeType_Subtract:      "Subtract",
+	NodeType_Multiply:      "Multiply",
+	NodeType_Divide:        "Divide",
+	NodeType_Modulo:        "Modulo",
+	NodeType_Negate:        "Negate",
+	NodeType_GreaterEquals: "GreaterEquals",
+	NodeType_LesserEquals:  "LesserEquals",
+	NodeType_Boolean:       "Boolean",
+	NodeType_Null:          "Null",
+	NodeType_List:          "List",
+	NodeType_Object:        "Object",
+	NodeType_In:            "In",
+	NodeType_Between:       "Between",
+	NodeType_Matches:       "Matches",
+	NodeType_StartsWith:    "StartsWith",
+	NodeType_EndsWith:      "EndsWith",
+	NodeType_Like:          "Like",
+	NodeType_Extract:       "Extract",
+	NodeType_Call:          "Call",
 }
 
 // Node is the most atomic piece of fpath syntax, describing both expressions
@@ -29,20 +103,79 @@ type Node interface {
 	Type() int
 }
 
-func (BlockNode) Type() int  { return NodeType_Block }
-func (NumberNode) Type() int { return NodeType_Number }
-func (EqualsNode) Type() int { return NodeType_Equals }
+func (BlockNode) Type() int         { return NodeType_Block }
+func (NumberNode) Type() int        { return NodeType_Number }
+func (EqualsNode) Type() int        { return NodeType_Equals }
+func (LabelNode) Type() int         { return NodeType_Label }
+func (FieldNode) Type() int         { return NodeType_Field }
+func (IndexNode) Type() int         { return NodeType_Index }
+func (SliceNode) Type() int         { return NodeType_Slice }
+func (WildcardNode) Type() int      { return NodeType_Wildcard }
+func (RecursiveNode) Type() int     { return NodeType_Recursive }
+func (ContextNode) Type() int       { return NodeType_Context }
+func (FilterNode) Type() int        { return NodeType_Filter }
+func (ContainsNode) Type() int      { return NodeType_Contains }
+func (GreaterNode) Type() int       { return NodeType_Greater }
+func (LesserNode) Type() int        { return NodeType_Lesser }
+func (NotNode) Type() int           { return NodeType_Not }
+func (NotOperationNode) Type() int  { return NodeType_NotOperation }
+func (AndNode) Type() int           { return NodeType_And }
+func (OrNode) Type() int            { return NodeType_Or }
+func (StringNode) Type() int        { return NodeType_String }
+func (AddNode) Type() int           { return NodeType_Add }
+func (SubtractNode) Type() int      { return NodeType_Subtract }
+func (MultiplyNode) Type() int      { return NodeType_Multiply }
+func (DivideNode) Type() int        { return NodeType_Divide }
+func (ModuloNode) Type() int        { return NodeType_Modulo }
+func (NegateNode) Type() int        { return NodeType_Negate }
+func (GreaterEqualsNode) Type() int { return NodeType_GreaterEquals }
+func (LesserEqualsNode) Type() int  { return NodeType_LesserEquals }
+func (BooleanNode) Type() int       { return NodeType_Boolean }
+func (NullNode) Type() int          { return NodeType_Null }
+func (ListNode) Type() int          { return NodeType_List }
+func (ObjectNode) Type() int        { return NodeType_Object }
+func (InNode) Type() int            { return NodeType_In }
+func (BetweenNode) Type() int       { return NodeType_Between }
+func (MatchesNode) Type() int       { return NodeType_Matches }
+func (StartsWithNode) Type() int    { return NodeType_StartsWith }
+func (EndsWithNode) Type() int      { return NodeType_EndsWith }
+func (LikeNode) Type() int          { return NodeType_Like }
+func (ExtractNode) Type() int       { return NodeType_Extract }
+func (CallNode) Type() int          { return NodeType_Call }
 
-// Expression nodes are evaluable in isolation of other nodes and don't depend
-// on external data.
+// Expression nodes are evaluable in isolation of other nodes, reading only from
+// the input data.
 type Expression interface {
 	Node
 
 	expression()
 }
 
-func (BlockNode) expression()  {}
-func (NumberNode) expression() {}
+func (BlockNode) expression()     {}
+func (NumberNode) expression()    {}
+func (LabelNode) expression()     {}
+func (FieldNode) expression()     {}
+func (IndexNode) expression()     {}
+func (SliceNode) expression()     {}
+func (WildcardNode) expression()  {}
+func (RecursiveNode) expression() {}
+func (ContextNode) expression()   {}
+func (FilterNode) expression()    {}
+func (NotNode) expression()       {}
+func (AndNode) expression()       {}
+func (OrNode) expression()        {}
+func (StringNode) expression()    {}
+func (AddNode) expression()       {}
+func (SubtractNode) expression()  {}
+func (MultiplyNode) expression()  {}
+func (DivideNode) expression()    {}
+func (ModuloNode) expression()    {}
+func (NegateNode) expression()    {}
+func (BooleanNode) expression()   {}
+func (NullNode) expression()      {}
+func (ListNode) expression()      {}
+func (ObjectNode) expression()    {}
+func (CallNode) expression()      {}
 
 // Operation nodes require an additional input to evaluate to a value.
 type Operation interface {
@@ -51,7 +184,29 @@ type Operation interface {
 	operation()
 }
 
-func (EqualsNode) operation() {}
+func (EqualsNode) operation()        {}
+func (ContainsNode) operation()      {}
+func (GreaterNode) operation()       {}
+func (LesserNode) operation()        {}
+func (GreaterEqualsNode) operation() {}
+func (LesserEqualsNode) operation()  {}
+func (InNode) operation()            {}
+func (BetweenNode) operation()       {}
+func (MatchesNode) operation()       {}
+func (StartsWithNode) operation()    {}
+func (EndsWithNode) operation()      {}
+func (LikeNode) operation()          {}
+func (ExtractNode) operation()       {}
+func (NotOperationNode) operation()  {}
+
+// nodeString returns a string representation of a node that may be nil.
+func nodeString(n Node) string {
+	if n == nil {
+		return "nil"
+	}
+
+	return n.String()
+}
 
 // BlockNode represents an executable fpath block that contains a base
 // expression and a collection of operations to perform on the expression.
@@ -84,6 +239,79 @@ func (n NumberNode) String() string {
 	return fmt.Sprintf("NumberNode{ Value: %s }", n.Value.String())
 }
 
+// StringNode represents a string literal.
+type StringNode struct {
+	Value string
+}
+
+// String returns a string representation of a StringNode.
+func (s StringNode) String() string {
+	return fmt.Sprintf("StringNode{ Value: %q }", s.Value)
+}
+
+// BooleanNode represents a boolean literal.
+type BooleanNode struct {
+	Value bool
+}
+
+// String returns a string representation of a BooleanNode.
+func (b BooleanNode) String() string {
+	return fmt.Sprintf("BooleanNode{ Value: %t }", b.Value)
+}
+
+// NullNode represents the null literal.
+type NullNode struct{}
+
+// String returns a string representation of a NullNode.
+func (NullNode) String() string {
+	return "NullNode{}"
+}
+
+// ListNode represents a list literal, whose elements may be any expression.
+type ListNode struct {
+	Elements []Expression
+}
+
+// String returns a string representation of a ListNode.
+func (l ListNode) String() string {
+	elementsStrings := make([]string, len(l.Elements))
+
+	for i, e := range l.Elements {
+		elementsStrings[i] = e.String()
+	}
+
+	elementsString := "[" + strings.Join(elementsStrings, ", ") + "]"
+
+	return fmt.Sprintf("ListNode{ Elements: %s }", elementsString)
+}
+
+// ObjectNode represents an object literal, whose fields may be any
+// expression.
+type ObjectNode struct {
+	Fields map[string]Expression
+}
+
+// String returns a string representation of an ObjectNode, with fields ordered
+// by key.
+func (o ObjectNode) String() string {
+	keys := make([]string, 0, len(o.Fields))
+
+	for k := range o.Fields {
+		keys = append(keys, k)
+	}
+
+	slices.Sort(keys)
+	fieldsStrings := make([]string, len(keys))
+
+	for i, k := range keys {
+		fieldsStrings[i] = fmt.Sprintf("%q: %s", k, o.Fields[k].String())
+	}
+
+	fieldsString := "{" + strings.Join(fieldsStrings, ", ") + "}"
+
+	return fmt.Sprintf("ObjectNode{ Fields: %s }", fieldsString)
+}
+
 // EqualsNode represents an operation that compares the current value with an
 // expression and updates the current value with the result.
 type EqualsNode struct {
@@ -94,3 +322,375 @@ type EqualsNode struct {
 func (e EqualsNode) String() string {
 	return fmt.Sprintf("EqualsNode{ Expression: %s }", e.Expression.String())
 }
+
+// ContainsNode represents an operation that checks whether the current value
+// contains the value of an expression and updates the current value with the
+// result.
+type ContainsNode struct {
+	Expression Expression
+}
+
+// String returns a string representation of a ContainsNode.
+func (c ContainsNode) String() string {
+	return fmt.Sprintf("ContainsNode{ Expression: %s }", c.Expression.String())
+}
+
+// GreaterNode represents an operation that checks whether the current value is
+// greater than the value of an expression and updates the current value with
+// the result.
+type GreaterNode struct {
+	Expression Expression
+}
+
+// String returns a string representation of a GreaterNode.
+func (g GreaterNode) String() string {
+	return fmt.Sprintf("GreaterNode{ Expression: %s }", g.Expression.String())
+}
+
+// LesserNode represents an operation that checks whether the current value is
+// lesser than the value of an expression and updates the current value with
+// the result.
+type LesserNode struct {
+	Expression Expression
+}
+
+// String returns a string representation of a LesserNode.
+func (l LesserNode) String() string {
+	return fmt.Sprintf("LesserNode{ Expression: %s }", l.Expression.String())
+}
+
+// GreaterEqualsNode represents an operation that checks whether the current
+// value is greater than or equal to the value of an expression and updates the
+// current value with the result.
+type GreaterEqualsNode struct {
+	Expression Expression
+}
+
+// String returns a string representation of a GreaterEqualsNode.
+func (g GreaterEqualsNode) String() string {
+	return fmt.Sprintf("GreaterEqualsNode{ Expression: %s }", g.Expression.String())
+}
+
+// LesserEqualsNode represents an operation that checks whether the current
+// value is lesser than or equal to the value of an expression and updates the
+// current value with the result.
+type LesserEqualsNode struct {
+	Expression Expression
+}
+
+// String returns a string representation of a LesserEqualsNode.
+func (l LesserEqualsNode) String() string {
+	return fmt.Sprintf("LesserEqualsNode{ Expression: %s }", l.Expression.String())
+}
+
+// InNode represents an operation that checks whether the current value is
+// contained by the value of an expression and updates the current value with
+// the result.
+type InNode struct {
+	Expression Expression
+}
+
+// String returns a string representation of an InNode.
+func (i InNode) String() string {
+	return fmt.Sprintf("InNode{ Expression: %s }", i.Expression.String())
+}
+
+// BetweenNode represents an operation that checks whether the current value is
+// within the inclusive range between the values of two expressions and updates
+// the current value with the result.
+type BetweenNode struct {
+	Lower Expression
+	Upper Expression
+}
+
+// String returns a string representation of a BetweenNode.
+func (b BetweenNode) String() string {
+	return fmt.Sprintf("BetweenNode{ Lower: %s, Upper: %s }", b.Lower.String(), b.Upper.String())
+}
+
+// MatchesNode represents an operation that checks whether the current value
+// matches a regular expression and updates the current value with the result.
+// The regular expression is compiled when the query is parsed.
+type MatchesNode struct {
+	Pattern *regexp.Regexp
+}
+
+// String returns a string representation of a MatchesNode.
+func (m MatchesNode) String() string {
+	return fmt.Sprintf("MatchesNode{ Pattern: %q }", m.Pattern.String())
+}
+
+// StartsWithNode represents an operation that checks whether the current value
+// starts with the value of an expression and updates the current value with
+// the result.
+type StartsWithNode struct {
+	Expression Expression
+}
+
+// String returns a string representation of a StartsWithNode.
+func (s StartsWithNode) String() string {
+	return fmt.Sprintf("StartsWithNode{ Expression: %s }", s.Expression.String())
+}
+
+// EndsWithNode represents an operation that checks whether the current value
+// ends with the value of an expression and updates the current value with the
+// result.
+type EndsWithNode struct {
+	Expression Expression
+}
+
+// String returns a string representation of an EndsWithNode.
+func (e EndsWithNode) String() string {
+	return fmt.Sprintf("EndsWithNode{ Expression: %s }", e.Expression.String())
+}
+
+// LikeNode represents an operation that checks whether the current value
+// matches an SQL style pattern, where % matches any run of characters and _
+// matches any single character, and updates the current value with the
+// result.
+// Regexp is the pattern translated into an anchored regular expression when the
+// query is parsed.
+type LikeNode struct {
+	Pattern string
+	Regexp  *regexp.Regexp
+}
+
+// String returns a string representation of a LikeNode.
+func (l LikeNode) String() string {
+	return fmt.Sprintf("LikeNode{ Pattern: %q }", l.Pattern)
+}
+
+// ExtractNode represents an operation that updates the current value with the
+// substrings captured by the first match of a regular expression within it.
+// The regular expression is compiled when the query is parsed.
+type ExtractNode struct {
+	Pattern *regexp.Regexp
+}
+
+// String returns a string representation of an ExtractNode.
+func (e ExtractNode) String() string {
+	return fmt.Sprintf("ExtractNode{ Pattern: %q }", e.Pattern.String())
+}
+
+// NotOperationNode represents an operation that applies another operation to
+// the current value and updates the current value with the inverse of the
+// result.
+type NotOperationNode struct {
+	Operation Operation
+}
+
+// String returns a string representation of a NotOperationNode.
+func (n NotOperationNode) String() string {
+	return fmt.Sprintf("NotOperationNode{ Operation: %s }", n.Operation.String())
+}
+
+// LabelNode represents a reference to a field of the input data.
+type LabelNode struct {
+	Value string
+}
+
+// String returns a string representation of a LabelNode.
+func (l LabelNode) String() string {
+	return fmt.Sprintf("LabelNode{ Value: %q }", l.Value)
+}
+
+// FieldNode represents a reference to a field of the value of an expression.
+type FieldNode struct {
+	Expression Expression
+	Label      string
+}
+
+// String returns a string representation of a FieldNode.
+func (f FieldNode) String() string {
+	return fmt.Sprintf("FieldNode{ Expression: %s, Label: %q }", f.Expression.String(), f.Label)
+}
+
+// IndexNode represents a reference to an element of the list value of an
+// expression.
+// Negative indexes count back from the end of the list. String indexes refer to
+// a field of an object value instead, like a FieldNode.
+type IndexNode struct {
+	Expression Expression
+	Index      Expression
+}
+
+// String returns a string representation of an IndexNode.
+func (i IndexNode) String() string {
+	return fmt.Sprintf("IndexNode{ Expression: %s, Index: %s }", i.Expression.String(), i.Index.String())
+}
+
+// SliceNode represents a reference to a range of elements of the list value of
+// an expression.
+// Start is inclusive and End is exclusive, and either may be nil to extend the
+// range to the start or end of the list.
+type SliceNode struct {
+	Expression Expression
+	Start      Expression
+	End        Expression
+}
+
+// String returns a string representation of a SliceNode.
+func (s SliceNode) String() string {
+	return fmt.Sprintf("SliceNode{ Expression: %s, Start: %s, End: %s }", s.Expression.String(), nodeString(s.Start), nodeString(s.End))
+}
+
+// WildcardNode represents a reference to every element of the list or object
+// value of an expression.
+type WildcardNode struct {
+	Expression Expression
+}
+
+// String returns a string representation of a WildcardNode.
+func (w WildcardNode) String() string {
+	return fmt.Sprintf("WildcardNode{ Expression: %s }", w.Expression.String())
+}
+
+// RecursiveNode represents a reference to every field with the label at any
+// depth within the value of an expression.
+type RecursiveNode struct {
+	Expression Expression
+	Label      string
+}
+
+// String returns a string representation of a RecursiveNode.
+func (r RecursiveNode) String() string {
+	return fmt.Sprintf("RecursiveNode{ Expression: %s, Label: %q }", r.Expression.String(), r.Label)
+}
+
+// ContextNode represents the input data as a whole.
+type ContextNode struct{}
+
+// String returns a string representation of a ContextNode.
+func (ContextNode) String() string {
+	return "ContextNode{}"
+}
+
+// FilterNode represents a reference to the elements of the list or object
+// value of an expression for which a condition holds.
+// The condition is evaluated with each element as its input data.
+type FilterNode struct {
+	Expression Expression
+	Condition  Expression
+}
+
+// String returns a string representation of a FilterNode.
+func (f FilterNode) String() string {
+	return fmt.Sprintf("FilterNode{ Expression: %s, Condition: %s }", f.Expression.String(), f.Condition.String())
+}
+
+// NotNode represents the inverse of a condition.
+type NotNode struct {
+	Expression Expression
+}
+
+// String returns a string representation of a NotNode.
+func (n NotNode) String() string {
+	return fmt.Sprintf("NotNode{ Expression: %s }", n.Expression.String())
+}
+
+// AndNode represents a condition that holds when both of its conditions hold.
+type AndNode struct {
+	Left  Expression
+	Right Expression
+}
+
+// String returns a string representation of an AndNode.
+func (a AndNode) String() string {
+	return fmt.Sprintf("AndNode{ Left: %s, Right: %s }", a.Left.String(), a.Right.String())
+}
+
+// OrNode represents a condition that holds when either of its conditions hold.
+type OrNode struct {
+	Left  Expression
+	Right Expression
+}
+
+// String returns a string representation of an OrNode.
+func (o OrNode) String() string {
+	return fmt.Sprintf("OrNode{ Left: %s, Right: %s }", o.Left.String(), o.Right.String())
+}
+
+// AddNode represents the sum of two numbers.
+type AddNode struct {
+	Left  Expression
+	Right Expression
+}
+
+// String returns a string representation of an AddNode.
+func (a AddNode) String() string {
+	return fmt.Sprintf("AddNode{ Left: %s, Right: %s }", a.Left.String(), a.Right.String())
+}
+
+// SubtractNode represents the difference of two numbers.
+type SubtractNode struct {
+	Left  Expression
+	Right Expression
+}
+
+// String returns a string representation of a SubtractNode.
+func (s SubtractNode) String() string {
+	return fmt.Sprintf("SubtractNode{ Left: %s, Right: %s }", s.Left.String(), s.Right.String())
+}
+
+// MultiplyNode represents the product of two numbers.
+type MultiplyNode struct {
+	Left  Expression
+	Right Expression
+}
+
+// String returns a string representation of a MultiplyNode.
+func (m MultiplyNode) String() string {
+	return fmt.Sprintf("MultiplyNode{ Left: %s, Right: %s }", m.Left.String(), m.Right.String())
+}
+
+// DivideNode represents the quotient of two numbers.
+type DivideNode struct {
+	Left  Expression
+	Right Expression
+}
+
+// String returns a string representation of a DivideNode.
+func (d DivideNode) String() string {
+	return fmt.Sprintf("DivideNode{ Left: %s, Right: %s }", d.Left.String(), d.Right.String())
+}
+
+// ModuloNode represents the remainder of dividing two numbers.
+type ModuloNode struct {
+	Left  Expression
+	Right Expression
+}
+
+// String returns a string representation of a ModuloNode.
+func (m ModuloNode) String() string {
+	return fmt.Sprintf("ModuloNode{ Left: %s, Right: %s }", m.Left.String(), m.Right.String())
+}
+
+// NegateNode represents the negation of a number.
+type NegateNode struct {
+	Expression Expression
+}
+
+// String returns a string representation of a NegateNode.
+func (n NegateNode) String() string {
+	return fmt.Sprintf("NegateNode{ Expression: %s }", n.Expression.String())
+}
+
+// CallNode represents a call to a built-in function with the values of its
+// arguments.
+type CallNode struct {
+	Name      string
+	Arguments []Expression
+}
+
+// String returns a string representation of a CallNode.
+func (c CallNode) String() string {
+	argumentsStrings := make([]string, len(c.Arguments))
+
+	for i, a := range c.Arguments {
+		argumentsStrings[i] = a.String()
+	}
+
+	argumentsString := "[" + strings.Join(argumentsStrings, ", ") + "]"
+
+	return fmt.Sprintf("CallNode{ Name: %q, Arguments: %s }", c.Name, argumentsString)
+}
@@ -0,0 +1,163 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Argument types are flags that can be combined to describe the values a
+// function argument accepts.
+const (
+	ArgumentType_Number = 1 << iota
+	ArgumentType_String
+	ArgumentType_Boolean
+	ArgumentType_List
+	ArgumentType_Object
+)
+
+var ArgumentTypeString map[int]string = map[int]string{
+	ArgumentType_Number:  "Number",
+	ArgumentType_String:  "String",
+	ArgumentType_Boolean: "Boolean",
+	ArgumentType_List:    "List",
+	ArgumentType_Object:  "Object",
+}
+
+// Function describes the signature of a built-in function.
+// Arguments holds the accepted types of each argument, the last Optional of
+// which may be omitted. Returns is the type of the function's result.
+type Function struct {
+	Arguments []int
+	Optional  int
+	Returns   int
+}
+
+// Functions maps the names of the built-in functions to their signatures.
+var Functions map[string]Function = map[string]Function{
+	"length": {Arguments: []int{ArgumentType_String | ArgumentType_List | ArgumentType_Object}, Returns: ArgumentType_Number},
+	"lower":  {Arguments: []int{ArgumentType_String}, Returns: ArgumentType_String},
+	"upper":  {Arguments: []int{ArgumentType_String}, Returns: ArgumentType_String},
+	"trim":   {Arguments: []int{ArgumentType_String}, Returns: ArgumentType_String},
+	"split":  {Arguments: []int{ArgumentType_String, ArgumentType_String}, Returns: ArgumentType_List},
+	"join":   {Arguments: []int{ArgumentType_List, ArgumentType_String}, Returns: ArgumentType_String},
+	"abs":    {Arguments: []int{ArgumentType_Number}, Returns: ArgumentType_Number},
+	"floor":  {Arguments: []int{ArgumentType_Number}, Returns: ArgumentType_Number},
+	"ceil":   {Arguments: []int{ArgumentType_Number}, Returns: ArgumentType_Number},
+	"round":  {Arguments: []int{ArgumentType_Number, ArgumentType_Number}, Optional: 1, Returns: ArgumentType_Number},
+	"keys":   {Arguments: []int{ArgumentType_Object}, Returns: ArgumentType_List},
+	"sum":    {Arguments: []int{ArgumentType_List}, Returns: ArgumentType_Number},
+}
+
+// ArgumentTypeNames returns a description of the argument types, such as
+// "String or List".
+func ArgumentTypeNames(argumentType int) string {
+	var names []string
+
+	for flag := ArgumentType_Number; flag <= ArgumentType_Object; flag <<= 1 {
+		if argumentType&flag != 0 {
+			names = append(names, ArgumentTypeString[flag])
+		}
+	}
+
+	return strings.Join(names, " or ")
+}
+
+// checkCall returns an error if the call's function doesn't exist, the call
+// has the wrong number of arguments, or an argument's type is known from the
+// query and isn't accepted by the function.
+// Arguments whose type depends on the input data are checked when the query is
+// evaluated instead.
+func checkCall(call CallNode, position int) (err error) {
+	function, ok := Functions[call.Name]
+
+	if !ok {
+		err = fmt.Errorf("unknown function %q at position %d", call.Name, position)
+		return
+	}
+
+	required := len(function.Arguments) - function.Optional
+
+	if len(call.Arguments) < required || len(call.Arguments) > len(function.Arguments) {
+		expected := fmt.Sprintf("%d arguments", len(function.Arguments))
+
+		switch {
+		case function.Optional > 0:
+			expected = fmt.Sprintf("%d to %d arguments", required, len(function.Arguments))
+		case len(function.Arguments) == 1:
+			expected = "1 argument"
+		}
+
+		err = fmt.Errorf("function %q at position %d expects %s but got %d", call.Name, position, expected, len(call.Arguments))
+		return
+	}
+
+	for i, argument := range call.Arguments {
+		argumentType, ok := staticType(argument)
+
+		if ok && argumentType&function.Arguments[i] == 0 {
+			err = fmt.Errorf("argument %d of function %q at position %d must be %s but got %s", i+1, call.Name, position, ArgumentTypeNames(function.Arguments[i]), ArgumentTypeNames(argumentType))
+			return
+		}
+	}
+
+	return nil
+}
+
+// staticType returns the type of value the expression evaluates to, if it can
+// be known without the input data.
+// Null is accepted by every function, so a null literal has no static type.
+// Arithmetic on a path may result in several values, which are passed to a
+// function as a list, so arithmetic is only a number if its operands' types
+// are known. A block is a boolean unless its last operation is an extract.
+func staticType(expression Expression) (argumentType int, ok bool) {
+	switch x := expression.(type) {
+	case NumberNode:
+		return ArgumentType_Number, true
+	case AddNode:
+		return arithmeticType(x.Left, x.Right)
+	case SubtractNode:
+		return arithmeticType(x.Left, x.Right)
+	case MultiplyNode:
+		return arithmeticType(x.Left, x.Right)
+	case DivideNode:
+		return arithmeticType(x.Left, x.Right)
+	case ModuloNode:
+		return arithmeticType(x.Left, x.Right)
+	case NegateNode:
+		return arithmeticType(x.Expression)
+	case StringNode:
+		return ArgumentType_String, true
+	case BooleanNode, NotNode, AndNode, OrNode:
+		return ArgumentType_Boolean, true
+	case BlockNode:
+		if len(x.Operations) == 0 {
+			return staticType(x.BaseExpression)
+		}
+
+		if _, ok := x.Operations[len(x.Operations)-1].(ExtractNode); ok {
+			return 0, false
+		}
+
+		return ArgumentType_Boolean, true
+	case ListNode:
+		return ArgumentType_List, true
+	case ObjectNode:
+		return ArgumentType_Object, true
+	case CallNode:
+		return Functions[x.Name].Returns, true
+	default:
+		return 0, false
+	}
+}
+
+// arithmeticType returns the static type of arithmetic on the operands, which
+// is a number if every operand's type is known.
+func arithmeticType(operands ...Expression) (argumentType int, ok bool) {
+	for _, operand := range operands {
+		if _, ok = staticType(operand); !ok {
+			return 0, false
+		}
+	}
+
+	return ArgumentType_Number, true
+}
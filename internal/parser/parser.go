@@ -1,13 +1,24 @@
 package parser
 
 import (
+	"errors"
 	"fmt"
 	"io"
+	"math/big"
+	"regexp"
+	"strings"
 
 	"github.com/fcutting/fpath/internal/lexer"
 	"github.com/shopspring/decimal"
 )
 
+var UnbalancedParentheses = errors.New("unbalanced parentheses")
+
+// MaxExponent is the largest exponent, positive or negative, a number in a
+// query can have. Larger exponents would make comparing and calculating with
+// the number take an unbounded amount of time and memory.
+const MaxExponent = 1000
+
 func NewParser(lexer *lexer.Lexer) *Parser {
 	return &Parser{
 		lexer: lexer,
@@ -19,22 +30,186 @@ type Parser struct {
 	lexer *lexer.Lexer
 }
 
+// ParseQuery returns the condition that makes up the whole query.
+// If there are tokens left over after the condition, ParseQuery returns an
+// error.
+func (p *Parser) ParseQuery() (query Expression, err error) {
+	query, err = p.ParseOr()
+
+	if err != nil {
+		err = fmt.Errorf("failed to parse condition: %w", err)
+		return
+	}
+
+	token, err := p.lexer.GetToken()
+
+	if err == io.EOF {
+		return query, nil
+	}
+
+	if err != nil {
+		err = fmt.Errorf("failed to get token: %w", err)
+		return
+	}
+
+	if token.Type == lexer.TokenType_CloseParan {
+		err = fmt.Errorf("%w: unexpected CloseParan", UnbalancedParentheses)
+		return
+	}
+
+	err = fmt.Errorf("unexpected token: %s", lexer.TokenTypeString[token.Type])
+	return
+}
+
+// ParseOr returns the next condition in the query, combining conditions
+// separated by or.
+// Or has a lower precedence than and, so "a and b or c" is equivalent to
+// "(a and b) or c".
+func (p *Parser) ParseOr() (condition Expression, err error) {
+	condition, err = p.ParseAnd()
+
+	if err != nil {
+		return
+	}
+
+	for {
+		var token lexer.Token
+		token, err = p.lexer.PeekToken()
+
+		if err == io.EOF {
+			return condition, nil
+		}
+
+		if err != nil {
+			err = fmt.Errorf("failed to peek token: %w", err)
+			return
+		}
+
+		if token.Type != lexer.TokenType_Or {
+			return condition, nil
+		}
+
+		p.lexer.GetToken()
+		or := OrNode{Left: condition}
+		or.Right, err = p.ParseAnd()
+
+		if err != nil {
+			err = fmt.Errorf("failed to parse right side of Or: %w", err)
+			return
+		}
+
+		condition = or
+	}
+}
+
+// ParseAnd returns the next condition in the query, combining conditions
+// separated by and.
+// And has a lower precedence than not, so "not (a) and b" is equivalent to
+// "(not (a)) and b".
+func (p *Parser) ParseAnd() (condition Expression, err error) {
+	condition, err = p.ParseNot()
+
+	if err != nil {
+		return
+	}
+
+	for {
+		var token lexer.Token
+		token, err = p.lexer.PeekToken()
+
+		if err == io.EOF {
+			return condition, nil
+		}
+
+		if err != nil {
+			err = fmt.Errorf("failed to peek token: %w", err)
+			return
+		}
+
+		if token.Type != lexer.TokenType_And {
+			return condition, nil
+		}
+
+		p.lexer.GetToken()
+		and := AndNode{Left: condition}
+		and.Right, err = p.ParseNot()
+
+		if err != nil {
+			err = fmt.Errorf("failed to parse right side of And: %w", err)
+			return
+		}
+
+		condition = and
+	}
+}
+
+// ParseNot returns the next condition in the query, which is either a block or
+// the inverse of another condition.
+// A block without operations is returned as its base expression.
+func (p *Parser) ParseNot() (condition Expression, err error) {
+	token, err := p.lexer.PeekToken()
+
+	if err != nil {
+		err = fmt.Errorf("failed to peek token: %w", err)
+		return
+	}
+
+	if token.Type == lexer.TokenType_Not {
+		p.lexer.GetToken()
+		var not NotNode
+		not.Expression, err = p.ParseNot()
+
+		if err != nil {
+			err = fmt.Errorf("failed to parse condition: %w", err)
+			return
+		}
+
+		return not, nil
+	}
+
+	block, err := p.ParseBlock()
+
+	if err != nil {
+		return
+	}
+
+	if len(block.Operations) == 0 {
+		return block.BaseExpression, nil
+	}
+
+	return block, nil
+}
+
 // ParseBlock returns the next block in the query.
+// The block ends at the first token that doesn't start an operation.
 func (p *Parser) ParseBlock() (block BlockNode, err error) {
 	block.BaseExpression, err = p.ParseExpression()
 
 	if err != nil {
 		err = fmt.Errorf("failed to parse expression: %w", err)
+		return
 	}
 
 	for {
-		var operation Operation
-		operation, err = p.ParseOperation()
+		var token lexer.Token
+		token, err = p.lexer.PeekToken()
 
 		if err == io.EOF {
 			break
 		}
 
+		if err != nil {
+			err = fmt.Errorf("failed to peek token: %w", err)
+			return
+		}
+
+		if !isOperation(token.Type) {
+			break
+		}
+
+		var operation Operation
+		operation, err = p.ParseOperation()
+
 		if err != nil {
 			err = fmt.Errorf("failed to parser operation: %w", err)
 			return
@@ -66,6 +241,32 @@ func (p *Parser) ParseOperation() (operation Operation, err error) {
 		return
 	case lexer.TokenType_Equals:
 		return p.ParseEquals()
+	case lexer.TokenType_Contains:
+		return p.ParseContains()
+	case lexer.TokenType_Greater:
+		return p.ParseGreater()
+	case lexer.TokenType_Lesser:
+		return p.ParseLesser()
+	case lexer.TokenType_GreaterEquals:
+		return p.ParseGreaterEquals()
+	case lexer.TokenType_LesserEquals:
+		return p.ParseLesserEquals()
+	case lexer.TokenType_In:
+		return p.ParseIn()
+	case lexer.TokenType_Between:
+		return p.ParseBetween()
+	case lexer.TokenType_Matches:
+		return p.ParseMatches()
+	case lexer.TokenType_StartsWith:
+		return p.ParseStartsWith()
+	case lexer.TokenType_EndsWith:
+		return p.ParseEndsWith()
+	case lexer.TokenType_Like:
+		return p.ParseLike()
+	case lexer.TokenType_Extract:
+		return p.ParseExtract()
+	case lexer.TokenType_Not:
+		return p.ParseNotOperation()
 	default:
 		err = fmt.Errorf("unsupported token type: %s", lexer.TokenTypeString[token.Type])
 		return
@@ -85,39 +286,1059 @@ func (p *Parser) ParseEquals() (equals EqualsNode, err error) {
 	return equals, nil
 }
 
-// ParseExpression returns the next expression in the query.
-// If the next token is not an expression, this step will return an error.
-func (p *Parser) ParseExpression() (expression Expression, err error) {
-	token, err := p.lexer.GetToken()
+// ParseContains returns a parsed ContainsNode assuming the current operation is
+// a contains operation.
+func (p *Parser) ParseContains() (contains ContainsNode, err error) {
+	contains.Expression, err = p.ParseExpression()
 
 	if err != nil {
-		err = fmt.Errorf("failed to get token: %w", err)
+		err = fmt.Errorf("failed to parse expression: %w", err)
 		return
 	}
 
-	switch token.Type {
-	case lexer.TokenType_Undefined:
-		err = fmt.Errorf("encountered undefined token: %q", token.Value)
+	return contains, nil
+}
+
+// ParseGreater returns a parsed GreaterNode assuming the current operation is a
+// greater operation.
+func (p *Parser) ParseGreater() (greater GreaterNode, err error) {
+	greater.Expression, err = p.ParseExpression()
+
+	if err != nil {
+		err = fmt.Errorf("failed to parse expression: %w", err)
 		return
-	case lexer.TokenType_Number:
-		return parseNumber(token)
-	default:
-		err = fmt.Errorf("unsupported token type: %s", lexer.TokenTypeString[token.Type])
+	}
+
+	return greater, nil
+}
+
+// ParseLesser returns a parsed LesserNode assuming the current operation is a
+// lesser operation.
+func (p *Parser) ParseLesser() (lesser LesserNode, err error) {
+	lesser.Expression, err = p.ParseExpression()
+
+	if err != nil {
+		err = fmt.Errorf("failed to parse expression: %w", err)
 		return
 	}
+
+	return lesser, nil
 }
 
-// parseNumber accepts a number token and converts it to a NumberNode.
-func parseNumber(token lexer.Token) (number NumberNode, err error) {
-	if token.Type != lexer.TokenType_Number {
-		err = fmt.Errorf("Token type is not a number: %v", token.Type)
+// ParseGreaterEquals returns a parsed GreaterEqualsNode assuming the current
+// operation is a greater or equals operation.
+func (p *Parser) ParseGreaterEquals() (greaterEquals GreaterEqualsNode, err error) {
+	greaterEquals.Expression, err = p.ParseExpression()
+
+	if err != nil {
+		err = fmt.Errorf("failed to parse expression: %w", err)
 		return
 	}
 
-	number.Value, err = decimal.NewFromString(token.Value)
+	return greaterEquals, nil
+}
+
+// ParseLesserEquals returns a parsed LesserEqualsNode assuming the current
+// operation is a lesser or equals operation.
+func (p *Parser) ParseLesserEquals() (lesserEquals LesserEqualsNode, err error) {
+	lesserEquals.Expression, err = p.ParseExpression()
 
 	if err != nil {
-		err = fmt.Errorf("failed to convert token value %q to number: %w", token.Value, err)
+		err = fmt.Errorf("failed to parse expression: %w", err)
+		return
+	}
+
+	return lesserEquals, nil
+}
+
+// ParseIn returns a parsed InNode assuming the current operation is an in
+// operation.
+// The operation's expression may be a parenthesized list of expressions
+// separated by whitespace or commas, such as (200 201 204), which is parsed as
+// a ListNode.
+// In a list separated by whitespace, a + or - between elements is ambiguous,
+// since (-1 -2) could be read as two numbers or as a subtraction, so it
+// returns an error. Such lists must be separated by commas, such as (-1, -2).
+// A parenthesized list is a list even with a single element, so v in (tags)
+// checks whether v equals tags, while v in tags checks whether v is one of the
+// elements of tags.
+func (p *Parser) ParseIn() (in InNode, err error) {
+	token, err := p.lexer.PeekToken()
+
+	if err != nil {
+		err = fmt.Errorf("failed to peek token: %w", err)
+		return
+	}
+
+	if token.Type != lexer.TokenType_OpenParan {
+		in.Expression, err = p.ParseExpression()
+
+		if err != nil {
+			err = fmt.Errorf("failed to parse expression: %w", err)
+			return
+		}
+
+		return in, nil
+	}
+
+	p.lexer.GetToken()
+	list := ListNode{Elements: []Expression{}}
+	var commas bool
+	var sign lexer.Token
+
+	for {
+		token, err = p.lexer.PeekToken()
+
+		if err == io.EOF {
+			err = fmt.Errorf("%w: missing CloseParan", UnbalancedParentheses)
+			return
+		}
+
+		if err != nil {
+			err = fmt.Errorf("failed to peek token: %w", err)
+			return
+		}
+
+		switch token.Type {
+		case lexer.TokenType_CloseParan:
+			if sign.Type != lexer.TokenType_Undefined && !commas {
+				err = fmt.Errorf("ambiguous %s at position %d in list separated by whitespace, separate elements with commas", lexer.TokenTypeString[sign.Type], sign.Position)
+				return
+			}
+
+			p.lexer.GetToken()
+			in.Expression = list
+			return in, nil
+		case lexer.TokenType_Comma:
+			if len(list.Elements) == 0 {
+				err = fmt.Errorf("expected expression but got Comma")
+				return
+			}
+
+			commas = true
+			p.lexer.GetToken()
+		}
+
+		var element Expression
+		element, err = p.ParseTerm()
+
+		if err != nil {
+			err = fmt.Errorf("failed to parse element: %w", err)
+			return
+		}
+
+		token, err = p.lexer.PeekToken()
+
+		if err == nil && sign.Type == lexer.TokenType_Undefined && (token.Type == lexer.TokenType_Plus || token.Type == lexer.TokenType_Minus) {
+			sign = token
+		}
+
+		element, err = p.parseSum(element)
+
+		if err != nil {
+			err = fmt.Errorf("failed to parse element: %w", err)
+			return
+		}
+
+		list.Elements = append(list.Elements, element)
+	}
+}
+
+// ParseBetween returns a parsed BetweenNode assuming the current operation is a
+// between operation.
+// The lower and upper bounds are separated by and.
+func (p *Parser) ParseBetween() (between BetweenNode, err error) {
+	between.Lower, err = p.ParseExpression()
+
+	if err != nil {
+		err = fmt.Errorf("failed to parse lower bound: %w", err)
+		return
+	}
+
+	if err = p.expect(lexer.TokenType_And); err != nil {
+		return
+	}
+
+	between.Upper, err = p.ParseExpression()
+
+	if err != nil {
+		err = fmt.Errorf("failed to parse upper bound: %w", err)
+		return
+	}
+
+	return between, nil
+}
+
+// ParseMatches returns a parsed MatchesNode assuming the current operation is a
+// matches operation.
+// The pattern must be a string literal holding an RE2 regular expression. If the
+// pattern is invalid, ParseMatches returns an error with the pattern's
+// position.
+// Patterns are best written as backtick raw strings, such as `\d+`, since a
+// double quoted string only accepts the escapes of string literals and rejects
+// regular expression escapes like "\d".
+func (p *Parser) ParseMatches() (matches MatchesNode, err error) {
+	matches.Pattern, err = p.parseRegexp()
+
+	if err != nil {
+		return
+	}
+
+	return matches, nil
+}
+
+// ParseExtract returns a parsed ExtractNode assuming the current operation is an
+// extract operation.
+// Like matches, the pattern must be a string literal holding an RE2 regular
+// expression, best written as a backtick raw string, such as `user=(\w+)`.
+func (p *Parser) ParseExtract() (extract ExtractNode, err error) {
+	extract.Pattern, err = p.parseRegexp()
+
+	if err != nil {
+		return
+	}
+
+	return extract, nil
+}
+
+// parseRegexp returns the compiled regular expression held by the next token.
+// If the pattern is invalid, parseRegexp returns an error with the pattern's
+// position.
+func (p *Parser) parseRegexp() (pattern *regexp.Regexp, err error) {
+	token, err := p.expectPattern()
+
+	if err != nil {
+		return
+	}
+
+	pattern, err = regexp.Compile(token.Value)
+
+	if err != nil {
+		err = fmt.Errorf("invalid pattern %q at position %d: %w", token.Value, token.Position, err)
+		return
+	}
+
+	return pattern, nil
+}
+
+// ParseStartsWith returns a parsed StartsWithNode assuming the current
+// operation is a starts with operation.
+func (p *Parser) ParseStartsWith() (startsWith StartsWithNode, err error) {
+	startsWith.Expression, err = p.ParseExpression()
+
+	if err != nil {
+		err = fmt.Errorf("failed to parse expression: %w", err)
+		return
+	}
+
+	return startsWith, nil
+}
+
+// ParseEndsWith returns a parsed EndsWithNode assuming the current operation is
+// an ends with operation.
+func (p *Parser) ParseEndsWith() (endsWith EndsWithNode, err error) {
+	endsWith.Expression, err = p.ParseExpression()
+
+	if err != nil {
+		err = fmt.Errorf("failed to parse expression: %w", err)
+		return
+	}
+
+	return endsWith, nil
+}
+
+// ParseLike returns a parsed LikeNode assuming the current operation is a like
+// operation.
+// The pattern must be a string literal, in which % matches any run of
+// characters, _ matches any single character and a backslash makes the
+// following character match itself.
+func (p *Parser) ParseLike() (like LikeNode, err error) {
+	token, err := p.expectPattern()
+
+	if err != nil {
+		return
+	}
+
+	var expression strings.Builder
+	expression.WriteString(`(?s)^`)
+	pattern := []rune(token.Value)
+
+	for i := 0; i < len(pattern); i++ {
+		switch pattern[i] {
+		case '%':
+			expression.WriteString(`.*`)
+		case '_':
+			expression.WriteString(`.`)
+		case '\\':
+			if i+1 == len(pattern) {
+				err = fmt.Errorf("invalid pattern %q at position %d: trailing backslash", token.Value, token.Position)
+				return
+			}
+
+			i++
+			expression.WriteString(regexp.QuoteMeta(string(pattern[i])))
+		default:
+			expression.WriteString(regexp.QuoteMeta(string(pattern[i])))
+		}
+	}
+
+	expression.WriteString(`$`)
+	like.Pattern = token.Value
+	like.Regexp = regexp.MustCompile(expression.String())
+	return like, nil
+}
+
+// expectPattern consumes the next token and returns an error if it isn't a
+// string literal, which patterns must be written as so they can be compiled
+// when the query is parsed.
+func (p *Parser) expectPattern() (token lexer.Token, err error) {
+	token, err = p.lexer.GetToken()
+
+	if err != nil {
+		err = fmt.Errorf("failed to get token: %w", err)
+		return
+	}
+
+	if token.Type != lexer.TokenType_StringLiteral {
+		err = fmt.Errorf("expected StringLiteral pattern but got %s", lexer.TokenTypeString[token.Type])
+		return
+	}
+
+	return token, nil
+}
+
+// ParseNotOperation returns a parsed NotOperationNode assuming the current
+// operation is a not operation.
+func (p *Parser) ParseNotOperation() (not NotOperationNode, err error) {
+	not.Operation, err = p.ParseOperation()
+
+	if err == io.EOF {
+		err = fmt.Errorf("expected operation after Not: %w", err)
+		return
+	}
+
+	if err != nil {
+		err = fmt.Errorf("failed to parse operation: %w", err)
+		return
+	}
+
+	return not, nil
+}
+
+// ParseExpression returns the next expression in the query, combining terms
+// separated by + or -.
+// Addition and subtraction have a lower precedence than multiplication,
+// division and modulo, so "a + b * c" is equivalent to "a + (b * c)".
+// If the next token is not an expression, this step will return an error.
+func (p *Parser) ParseExpression() (expression Expression, err error) {
+	expression, err = p.ParseTerm()
+
+	if err != nil {
+		return
+	}
+
+	return p.parseSum(expression)
+}
+
+// parseSum returns the expression made by adding or subtracting any terms that
+// follow the left term.
+func (p *Parser) parseSum(left Expression) (expression Expression, err error) {
+	expression = left
+
+	for {
+		var token lexer.Token
+		token, err = p.lexer.PeekToken()
+
+		if err == io.EOF {
+			return expression, nil
+		}
+
+		if err != nil {
+			err = fmt.Errorf("failed to peek token: %w", err)
+			return
+		}
+
+		if token.Type != lexer.TokenType_Plus && token.Type != lexer.TokenType_Minus {
+			return expression, nil
+		}
+
+		p.lexer.GetToken()
+		var right Expression
+		right, err = p.ParseTerm()
+
+		if err != nil {
+			err = fmt.Errorf("failed to parse right side of %s: %w", lexer.TokenTypeString[token.Type], err)
+			return
+		}
+
+		if token.Type == lexer.TokenType_Plus {
+			expression = AddNode{Left: expression, Right: right}
+		} else {
+			expression = SubtractNode{Left: expression, Right: right}
+		}
+	}
+}
+
+// ParseTerm returns the next term in the query, combining factors separated
+// by *, / or %.
+func (p *Parser) ParseTerm() (term Expression, err error) {
+	term, err = p.ParseFactor()
+
+	if err != nil {
+		return
+	}
+
+	for {
+		var token lexer.Token
+		token, err = p.lexer.PeekToken()
+
+		if err == io.EOF {
+			return term, nil
+		}
+
+		if err != nil {
+			err = fmt.Errorf("failed to peek token: %w", err)
+			return
+		}
+
+		switch token.Type {
+		case lexer.TokenType_Asterisk, lexer.TokenType_Slash, lexer.TokenType_Percent:
+		default:
+			return term, nil
+		}
+
+		p.lexer.GetToken()
+		var right Expression
+		right, err = p.ParseFactor()
+
+		if err != nil {
+			err = fmt.Errorf("failed to parse right side of %s: %w", lexer.TokenTypeString[token.Type], err)
+			return
+		}
+
+		switch token.Type {
+		case lexer.TokenType_Asterisk:
+			term = MultiplyNode{Left: term, Right: right}
+		case lexer.TokenType_Slash:
+			term = DivideNode{Left: term, Right: right}
+		case lexer.TokenType_Percent:
+			term = ModuloNode{Left: term, Right: right}
+		}
+	}
+}
+
+// ParseFactor returns the next factor in the query, which is either a primary
+// expression or the negation of another factor.
+func (p *Parser) ParseFactor() (factor Expression, err error) {
+	token, err := p.lexer.GetToken()
+
+	if err != nil {
+		err = fmt.Errorf("failed to get token: %w", err)
+		return
+	}
+
+	if token.Type == lexer.TokenType_Minus {
+		var negate NegateNode
+		negate.Expression, err = p.ParseFactor()
+
+		if err != nil {
+			err = fmt.Errorf("failed to parse expression after Minus: %w", err)
+			return
+		}
+
+		return negate, nil
+	}
+
+	return p.ParsePrimary(token)
+}
+
+// ParsePrimary returns the expression that starts with the token, followed by
+// any path segments.
+// A dot that isn't followed by a label refers to the input itself, such as the
+// current element in tags[. equals "beta"].
+// If the token doesn't start an expression, this step will return an error.
+func (p *Parser) ParsePrimary(token lexer.Token) (expression Expression, err error) {
+	switch token.Type {
+	case lexer.TokenType_Undefined:
+		err = fmt.Errorf("encountered undefined token: %q", token.Value)
+		return
+	case lexer.TokenType_Number:
+		expression, err = parseNumber(token)
+	case lexer.TokenType_StringLiteral:
+		expression = StringNode{Value: token.Value}
+	case lexer.TokenType_True:
+		expression = BooleanNode{Value: true}
+	case lexer.TokenType_False:
+		expression = BooleanNode{Value: false}
+	case lexer.TokenType_Null:
+		expression = NullNode{}
+	case lexer.TokenType_Label:
+		var next lexer.Token
+		next, err = p.lexer.PeekToken()
+
+		if err != nil && err != io.EOF {
+			err = fmt.Errorf("failed to peek token: %w", err)
+			return
+		}
+
+		err = nil
+
+		if next.Type == lexer.TokenType_OpenParan {
+			p.lexer.GetToken()
+			expression, err = p.ParseCall(token)
+		} else {
+			expression = LabelNode{Value: token.Value}
+		}
+	case lexer.TokenType_Dot:
+		var next lexer.Token
+		next, err = p.lexer.PeekToken()
+
+		if err != nil && err != io.EOF {
+			err = fmt.Errorf("failed to peek token: %w", err)
+			return
+		}
+
+		if err == nil && isLabel(next.Type) {
+			expression, err = p.ParseField(ContextNode{})
+		} else {
+			expression, err = ContextNode{}, nil
+		}
+	case lexer.TokenType_DotDot:
+		expression, err = p.ParseRecursive(ContextNode{})
+	case lexer.TokenType_OpenParan:
+		expression, err = p.ParseGroup()
+	case lexer.TokenType_OpenBracket:
+		expression, err = p.ParseList()
+	case lexer.TokenType_OpenBrace:
+		expression, err = p.ParseObject()
+	default:
+		err = fmt.Errorf("unsupported token type: %s", lexer.TokenTypeString[token.Type])
+		return
+	}
+
+	if err != nil {
+		return
+	}
+
+	return p.ParsePath(expression)
+}
+
+// ParseGroup returns the condition between a pair of parentheses assuming the
+// opening parenthesis has been consumed.
+// If the closing parenthesis is missing, ParseGroup returns an
+// UnbalancedParentheses error.
+func (p *Parser) ParseGroup() (group Expression, err error) {
+	if _, err = p.lexer.PeekToken(); err == io.EOF {
+		err = fmt.Errorf("%w: missing CloseParan", UnbalancedParentheses)
+		return
+	}
+
+	group, err = p.ParseOr()
+
+	if err != nil {
+		err = fmt.Errorf("failed to parse condition: %w", err)
+		return
+	}
+
+	token, err := p.lexer.GetToken()
+
+	if err == io.EOF {
+		err = fmt.Errorf("%w: missing CloseParan", UnbalancedParentheses)
+		return
+	}
+
+	if err != nil {
+		err = fmt.Errorf("failed to get token: %w", err)
+		return
+	}
+
+	if token.Type != lexer.TokenType_CloseParan {
+		err = fmt.Errorf("%w: expected CloseParan but got %s", UnbalancedParentheses, lexer.TokenTypeString[token.Type])
+		return
+	}
+
+	return group, nil
+}
+
+// ParseCall returns a parsed CallNode assuming the current expression is a call
+// to the function named by the label token and the opening parenthesis has been
+// consumed.
+// Arguments are separated by commas. If the function doesn't exist, is called
+// with the wrong number of arguments, or is passed a literal of a type it
+// doesn't accept, ParseCall returns an error with the call's position.
+func (p *Parser) ParseCall(label lexer.Token) (call CallNode, err error) {
+	call.Name = strings.ToLower(label.Value)
+	call.Arguments = []Expression{}
+	token, err := p.lexer.PeekToken()
+
+	if err == io.EOF {
+		err = fmt.Errorf("%w: missing CloseParan", UnbalancedParentheses)
+		return
+	}
+
+	if err != nil {
+		err = fmt.Errorf("failed to peek token: %w", err)
+		return
+	}
+
+	if token.Type == lexer.TokenType_CloseParan {
+		p.lexer.GetToken()
+		return call, checkCall(call, label.Position)
+	}
+
+	for {
+		var argument Expression
+		argument, err = p.ParseOr()
+
+		if err != nil {
+			err = fmt.Errorf("failed to parse argument: %w", err)
+			return
+		}
+
+		call.Arguments = append(call.Arguments, argument)
+		token, err = p.lexer.GetToken()
+
+		if err == io.EOF {
+			err = fmt.Errorf("%w: missing CloseParan", UnbalancedParentheses)
+			return
+		}
+
+		if err != nil {
+			err = fmt.Errorf("failed to get token: %w", err)
+			return
+		}
+
+		switch token.Type {
+		case lexer.TokenType_CloseParan:
+			return call, checkCall(call, label.Position)
+		case lexer.TokenType_Comma:
+		default:
+			err = fmt.Errorf("expected Comma or CloseParan but got %s", lexer.TokenTypeString[token.Type])
+			return
+		}
+	}
+}
+
+// ParseList returns a parsed ListNode assuming the opening bracket has been
+// consumed.
+// Elements are separated by commas.
+func (p *Parser) ParseList() (list ListNode, err error) {
+	list.Elements = []Expression{}
+	token, err := p.lexer.PeekToken()
+
+	if err != nil {
+		err = fmt.Errorf("failed to peek token: %w", err)
+		return
+	}
+
+	if token.Type == lexer.TokenType_CloseBracket {
+		p.lexer.GetToken()
+		return list, nil
+	}
+
+	for {
+		var element Expression
+		element, err = p.ParseOr()
+
+		if err != nil {
+			err = fmt.Errorf("failed to parse element: %w", err)
+			return
+		}
+
+		list.Elements = append(list.Elements, element)
+		token, err = p.lexer.GetToken()
+
+		if err != nil {
+			err = fmt.Errorf("failed to get token: %w", err)
+			return
+		}
+
+		switch token.Type {
+		case lexer.TokenType_CloseBracket:
+			return list, nil
+		case lexer.TokenType_Comma:
+		default:
+			err = fmt.Errorf("expected Comma or CloseBracket but got %s", lexer.TokenTypeString[token.Type])
+			return
+		}
+	}
+}
+
+// ParseObject returns a parsed ObjectNode assuming the opening brace has been
+// consumed.
+// Fields are separated by commas, and each field is a label or quoted label
+// followed by a colon and the field's value.
+// If a key appears more than once, ParseObject returns an error.
+func (p *Parser) ParseObject() (object ObjectNode, err error) {
+	object.Fields = map[string]Expression{}
+	token, err := p.lexer.PeekToken()
+
+	if err != nil {
+		err = fmt.Errorf("failed to peek token: %w", err)
+		return
+	}
+
+	if token.Type == lexer.TokenType_CloseBrace {
+		p.lexer.GetToken()
+		return object, nil
+	}
+
+	for {
+		token, err = p.lexer.GetToken()
+
+		if err != nil {
+			err = fmt.Errorf("failed to get token: %w", err)
+			return
+		}
+
+		if !isLabel(token.Type) {
+			err = fmt.Errorf("expected Label or StringLiteral but got %s", lexer.TokenTypeString[token.Type])
+			return
+		}
+
+		key := token.Value
+
+		if _, ok := object.Fields[key]; ok {
+			err = fmt.Errorf("duplicate key %q at position %d", key, token.Position)
+			return
+		}
+
+		if err = p.expect(lexer.TokenType_Colon); err != nil {
+			return
+		}
+
+		object.Fields[key], err = p.ParseOr()
+
+		if err != nil {
+			err = fmt.Errorf("failed to parse value of %q: %w", key, err)
+			return
+		}
+
+		token, err = p.lexer.GetToken()
+
+		if err != nil {
+			err = fmt.Errorf("failed to get token: %w", err)
+			return
+		}
+
+		switch token.Type {
+		case lexer.TokenType_CloseBrace:
+			return object, nil
+		case lexer.TokenType_Comma:
+		default:
+			err = fmt.Errorf("expected Comma or CloseBrace but got %s", lexer.TokenTypeString[token.Type])
+			return
+		}
+	}
+}
+
+// ParsePath returns the expression wrapped in any path segments that follow
+// it in the query.
+func (p *Parser) ParsePath(expression Expression) (path Expression, err error) {
+	path = expression
+
+	for {
+		var token lexer.Token
+		token, err = p.lexer.PeekToken()
+
+		if err == io.EOF {
+			return path, nil
+		}
+
+		if err != nil {
+			err = fmt.Errorf("failed to peek token: %w", err)
+			return
+		}
+
+		switch token.Type {
+		case lexer.TokenType_Dot:
+			p.lexer.GetToken()
+			path, err = p.ParseField(path)
+		case lexer.TokenType_OpenBracket:
+			p.lexer.GetToken()
+			path, err = p.ParseIndex(path)
+		case lexer.TokenType_DotDot:
+			p.lexer.GetToken()
+			path, err = p.ParseRecursive(path)
+		default:
+			return path, nil
+		}
+
+		if err != nil {
+			return
+		}
+	}
+}
+
+// ParseField returns a parsed FieldNode assuming the current path segment is a
+// field.
+// The field's label may be quoted, such as ."first name", to refer to keys that
+// aren't valid labels. Keywords are accepted as labels, such as order.contains.
+func (p *Parser) ParseField(expression Expression) (field FieldNode, err error) {
+	token, err := p.lexer.GetToken()
+
+	if err != nil {
+		err = fmt.Errorf("failed to get token: %w", err)
+		return
+	}
+
+	if !isLabel(token.Type) && !lexer.IsKeyword(token) {
+		err = fmt.Errorf("expected Label after Dot but got %s", lexer.TokenTypeString[token.Type])
+		return
+	}
+
+	field.Expression = expression
+	field.Label = token.Value
+	return field, nil
+}
+
+// ParseRecursive returns a parsed RecursiveNode assuming the current path
+// segment is a recursive descent.
+// Like fields, the label may be quoted or a keyword.
+func (p *Parser) ParseRecursive(expression Expression) (recursive RecursiveNode, err error) {
+	token, err := p.lexer.GetToken()
+
+	if err != nil {
+		err = fmt.Errorf("failed to get token: %w", err)
+		return
+	}
+
+	if !isLabel(token.Type) && !lexer.IsKeyword(token) {
+		err = fmt.Errorf("expected Label after DotDot but got %s", lexer.TokenTypeString[token.Type])
+		return
+	}
+
+	recursive.Expression = expression
+	recursive.Label = token.Value
+	return recursive, nil
+}
+
+// ParseIndex returns a parsed IndexNode, SliceNode, WildcardNode or FilterNode
+// assuming the current path segment is an index, slice, wildcard or filter.
+// A bracketed condition is a filter, while a bracketed expression is an index.
+// A bracketed path, such as items[active], is a filter of the elements where
+// the path equals true.
+func (p *Parser) ParseIndex(expression Expression) (index Expression, err error) {
+	var start, end Expression
+	token, err := p.lexer.PeekToken()
+
+	if err != nil {
+		err = fmt.Errorf("failed to peek token: %w", err)
+		return
+	}
+
+	if token.Type == lexer.TokenType_Asterisk {
+		p.lexer.GetToken()
+
+		if err = p.expect(lexer.TokenType_CloseBracket); err != nil {
+			return
+		}
+
+		return WildcardNode{Expression: expression}, nil
+	}
+
+	if token.Type != lexer.TokenType_Colon {
+		var condition Expression
+		condition, err = p.ParseOr()
+
+		if err != nil {
+			err = fmt.Errorf("failed to parse condition: %w", err)
+			return
+		}
+
+		if isCondition(condition) {
+			if err = p.expect(lexer.TokenType_CloseBracket); err != nil {
+				return
+			}
+
+			return FilterNode{Expression: expression, Condition: condition}, nil
+		}
+
+		token, err = p.lexer.PeekToken()
+
+		if err != nil && err != io.EOF {
+			err = fmt.Errorf("failed to peek token: %w", err)
+			return
+		}
+
+		if err == nil && token.Type == lexer.TokenType_CloseBracket && isPath(condition) {
+			p.lexer.GetToken()
+			condition = BlockNode{
+				BaseExpression: condition,
+				Operations:     []Operation{EqualsNode{Expression: BooleanNode{Value: true}}},
+			}
+
+			return FilterNode{Expression: expression, Condition: condition}, nil
+		}
+
+		start = condition
+	}
+
+	token, err = p.lexer.GetToken()
+
+	if err != nil {
+		err = fmt.Errorf("failed to get token: %w", err)
+		return
+	}
+
+	switch token.Type {
+	case lexer.TokenType_CloseBracket:
+		return IndexNode{Expression: expression, Index: start}, nil
+	case lexer.TokenType_Colon:
+	default:
+		err = fmt.Errorf("expected CloseBracket or Colon but got %s", lexer.TokenTypeString[token.Type])
+		return
+	}
+
+	token, err = p.lexer.PeekToken()
+
+	if err != nil {
+		err = fmt.Errorf("failed to peek token: %w", err)
+		return
+	}
+
+	if token.Type != lexer.TokenType_CloseBracket {
+		end, err = p.ParseExpression()
+
+		if err != nil {
+			err = fmt.Errorf("failed to parse expression: %w", err)
+			return
+		}
+	}
+
+	if err = p.expect(lexer.TokenType_CloseBracket); err != nil {
+		return
+	}
+
+	return SliceNode{Expression: expression, Start: start, End: end}, nil
+}
+
+// isLabel returns whether the token type can name a field, which is the case
+// for labels and quoted labels.
+func isLabel(tokenType int) bool {
+	return tokenType == lexer.TokenType_Label || tokenType == lexer.TokenType_StringLiteral
+}
+
+// isOperation returns whether the token type starts an operation.
+func isOperation(tokenType int) bool {
+	switch tokenType {
+	case lexer.TokenType_Equals, lexer.TokenType_Contains,
+		lexer.TokenType_Greater, lexer.TokenType_Lesser,
+		lexer.TokenType_GreaterEquals, lexer.TokenType_LesserEquals,
+		lexer.TokenType_In, lexer.TokenType_Between,
+		lexer.TokenType_Matches, lexer.TokenType_StartsWith,
+		lexer.TokenType_EndsWith, lexer.TokenType_Like,
+		lexer.TokenType_Extract, lexer.TokenType_Not:
+		return true
+	default:
+		return false
+	}
+}
+
+// isCondition returns whether the expression is a condition rather than a
+// plain expression, which is the case for blocks and the nodes that combine
+// conditions.
+func isCondition(expression Expression) bool {
+	switch x := expression.(type) {
+	case BlockNode:
+		return len(x.Operations) > 0
+	case NotNode, AndNode, OrNode:
+		return true
+	default:
+		return false
+	}
+}
+
+// isPath returns whether the expression is a path that reads from the input,
+// which is the case for labels, the context and the segments that follow them.
+func isPath(expression Expression) bool {
+	switch x := expression.(type) {
+	case LabelNode, ContextNode:
+		return true
+	case FieldNode:
+		return isPath(x.Expression)
+	case RecursiveNode:
+		return isPath(x.Expression)
+	case IndexNode:
+		return isPath(x.Expression)
+	case SliceNode:
+		return isPath(x.Expression)
+	case WildcardNode:
+		return isPath(x.Expression)
+	case FilterNode:
+		return isPath(x.Expression)
+	default:
+		return false
+	}
+}
+
+// expect consumes the next token and returns an error if it isn't of the
+// expected type.
+func (p *Parser) expect(tokenType int) (err error) {
+	token, err := p.lexer.GetToken()
+
+	if err != nil {
+		err = fmt.Errorf("failed to get token: %w", err)
+		return
+	}
+
+	if token.Type != tokenType {
+		err = fmt.Errorf("expected %s but got %s", lexer.TokenTypeString[tokenType], lexer.TokenTypeString[token.Type])
+		return
+	}
+
+	return nil
+}
+
+// parseNumber accepts a number token and converts it to a NumberNode.
+// Digit separators are ignored, and hexadecimal and binary integers are
+// converted to their decimal value.
+// Numbers with an exponent beyond MaxExponent are rejected.
+func parseNumber(token lexer.Token) (number NumberNode, err error) {
+	if token.Type != lexer.TokenType_Number {
+		err = fmt.Errorf("Token type is not a number: %v", token.Type)
+		return
+	}
+
+	value := strings.ReplaceAll(token.Value, "_", "")
+	sign, digits := "", value
+
+	if strings.HasPrefix(digits, "-") || strings.HasPrefix(digits, "+") {
+		sign, digits = digits[:1], digits[1:]
+	}
+
+	if len(digits) > 2 && digits[0] == '0' {
+		var base int
+
+		switch digits[1] {
+		case 'x', 'X':
+			base = 16
+		case 'b', 'B':
+			base = 2
+		}
+
+		if base != 0 {
+			integer, ok := new(big.Int).SetString(sign+digits[2:], base)
+
+			if !ok {
+				err = fmt.Errorf("failed to convert token value %q to number", token.Value)
+				return
+			}
+
+			number.Value = decimal.NewFromBigInt(integer, 0)
+			return number, nil
+		}
+	}
+
+	number.Value, err = decimal.NewFromString(value)
+
+	if err != nil {
+		err = fmt.Errorf("failed to convert token value %q to number: %w", token.Value, err)
+		return
+	}
+
+	if exponent := number.Value.Exponent(); exponent > MaxExponent || exponent < -MaxExponent {
+		err = fmt.Errorf("exponent of number %q at position %d must be between %d and %d", token.Value, token.Position, -MaxExponent, MaxExponent)
 		return
 	}
 
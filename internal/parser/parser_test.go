@@ -1,6 +1,7 @@
 package parser
 
 import (
+	"errors"
 	"os"
 	"testing"
 
@@ -21,6 +22,24 @@ func Test_Parse_ParseBlock(t *testing.T) {
 		"Equals": {
 			input: "2 equals 4",
 		},
+		"Path": {
+			input: "order.total equals 100",
+		},
+		"Contains": {
+			input: "tags contains tag",
+		},
+		"Chained": {
+			input: "tags contains tag equals 1",
+		},
+		"Greater": {
+			input: "order.total greater 100",
+		},
+		"Lesser": {
+			input: "order.total lesser 100",
+		},
+		"String": {
+			input: `name equals "bob"`,
+		},
 	}
 
 	for name, tc := range testCases {
@@ -38,6 +57,249 @@ func Test_Parse_ParseBlock(t *testing.T) {
 	}
 }
 
+func Test_Parse_ParseQuery(t *testing.T) {
+	testCases := map[string]struct {
+		input string
+	}{
+		"Equals": {
+			input: "2 equals 4",
+		},
+		"Expression": {
+			input: "items[0]",
+		},
+		"Not operation": {
+			input: "status not equals 1",
+		},
+		"Not": {
+			input: "not (status equals 1)",
+		},
+		"Not nested": {
+			input: "not (not (status contains 1))",
+		},
+		"Not filter": {
+			input: "items[not (status equals 1)]",
+		},
+		"And": {
+			input: "age greater 18 and country equals 1",
+		},
+		"Or": {
+			input: "a equals 1 or b equals 2 or c equals 3",
+		},
+		"Precedence": {
+			input: "a equals 1 or b equals 2 and c equals 3",
+		},
+		"Precedence not": {
+			input: "not (a equals 1) and b equals 2",
+		},
+		"Not or": {
+			input: "not (a equals 1 or b equals 2)",
+		},
+		"Filter and": {
+			input: "items[price greater 10 and stock greater 0].sku",
+		},
+		"Not block": {
+			input: "not status equals 1",
+		},
+		"Group": {
+			input: "(a equals 1 or b equals 2) and c equals 3",
+		},
+		"Group nested": {
+			input: "((a equals 1))",
+		},
+		"Group operations": {
+			input: "(a equals 1) equals b",
+		},
+		"Group path": {
+			input: "(items[0]).sku",
+		},
+		"Arithmetic operation": {
+			input: "price * quantity greater 1000",
+		},
+		"Arithmetic both sides": {
+			input: "a + 1 equals b - 1",
+		},
+		"Greater equals": {
+			input: "age >= 18",
+		},
+		"Lesser equals": {
+			input: "age <= 65",
+		},
+		"List literal": {
+			input: `tags equals ["a", "b"]`,
+		},
+		"In": {
+			input: "code in (200 201 204)",
+		},
+		"In commas": {
+			input: "code in (200, 201, -1)",
+		},
+		"In empty": {
+			input: "code in ()",
+		},
+		"In negative commas": {
+			input: "x in (-1, -2)",
+		},
+		"In leading negative": {
+			input: "x in (-1 2 3)",
+		},
+		"In arithmetic commas": {
+			input: "x in (a - 1, a + 1)",
+		},
+		"In parenthesized arithmetic": {
+			input: "x in ((a - 1) 2)",
+		},
+		"In single field": {
+			input: "v in (tags)",
+		},
+		"In list": {
+			input: `status in ["open", "pending"]`,
+		},
+		"In field": {
+			input: "code in codes",
+		},
+		"Not in": {
+			input: "code not in (200 201)",
+		},
+		"Between": {
+			input: "latency between 100 and 500",
+		},
+		"Between and": {
+			input: "latency between 100 and 500 and code in (200)",
+		},
+		"Between expressions": {
+			input: "latency between min * 2 and max - 1",
+		},
+		"Matches": {
+			input: "path matches `^/api/v[0-9]+`",
+		},
+		"Starts with": {
+			input: `path startswith "/api" and path ends with ".json"`,
+		},
+		"Like": {
+			input: `name like "J_n%"`,
+		},
+		"Not like": {
+			input: `name not like "%bot%"`,
+		},
+		"Extract": {
+			input: "message extract `user=(\\w+)` equals \"bob\"",
+		},
+		"Call operation": {
+			input: `lower(name) equals "bob" and length(items) greater 2`,
+		},
+		"Boolean literal": {
+			input: "active equals true and deleted_at equals null",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			lexer := lexer.NewLexer(tc.input)
+			parser := NewParser(lexer)
+			query, err := parser.ParseQuery()
+
+			if err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+
+			snaps.MatchSnapshot(t, query.String())
+		})
+	}
+}
+
+func Test_Parse_ParseQuery_Error(t *testing.T) {
+	testCases := map[string]struct {
+		input string
+	}{
+		"In unclosed": {
+			input: "code in (200 201",
+		},
+		"In ambiguous minus": {
+			input: "x in (-1 -2)",
+		},
+		"In ambiguous arithmetic": {
+			input: "x in (1 a + 1)",
+		},
+		"In leading comma": {
+			input: "code in (, 200)",
+		},
+		"In condition": {
+			input: "code in (a equals 1)",
+		},
+		"Matches invalid": {
+			input: `path matches "[a-"`,
+		},
+		"Matches expression": {
+			input: "path matches pattern",
+		},
+		"Like trailing backslash": {
+			input: "name like `100\\`",
+		},
+		"Like EOF": {
+			input: "name like",
+		},
+		"Extract invalid": {
+			input: "message extract `(?P<x`",
+		},
+		"Extract double quoted escape": {
+			input: `message extract "user=(\w+)"`,
+		},
+		"Between missing and": {
+			input: "latency between 100 500",
+		},
+		"Between missing upper": {
+			input: "latency between 100 and",
+		},
+		"Trailing expression": {
+			input: "2 4",
+		},
+		"Trailing bracket": {
+			input: "2 equals 4]",
+		},
+		"Unbalanced open": {
+			input: "(a equals 1 or b equals 2",
+		},
+		"Unbalanced close": {
+			input: "a equals 1 or b equals 2)",
+		},
+		"Unbalanced nested": {
+			input: "((a equals 1) and b equals 2",
+		},
+		"Group bracket": {
+			input: "(a equals 1]",
+		},
+		"Not unclosed": {
+			input: "not (status equals 1",
+		},
+		"Not operation EOF": {
+			input: "status not",
+		},
+		"Not operation expression": {
+			input: "status not 1",
+		},
+		"And EOF": {
+			input: "a equals 1 and",
+		},
+		"Or operation": {
+			input: "a equals 1 or equals 2",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			lexer := lexer.NewLexer(tc.input)
+			parser := NewParser(lexer)
+			_, err := parser.ParseQuery()
+
+			if err == nil {
+				t.Fatalf("Expected error but none returned")
+			}
+
+			snaps.MatchSnapshot(t, err.Error())
+		})
+	}
+}
+
 func Test_Parse_ParseOperation(t *testing.T) {
 	testCases := map[string]struct {
 		input string
@@ -45,6 +307,15 @@ func Test_Parse_ParseOperation(t *testing.T) {
 		"Equals": {
 			input: "equals 2",
 		},
+		"Contains": {
+			input: "contains 2",
+		},
+		"Greater": {
+			input: "greater 2",
+		},
+		"Lesser": {
+			input: "lesser 2",
+		},
 	}
 
 	for name, tc := range testCases {
@@ -75,6 +346,19 @@ func Test_Parse_ParseEquals(t *testing.T) {
 	snaps.MatchSnapshot(t, equals.String())
 }
 
+func Test_Parse_ParseContains(t *testing.T) {
+	input := "2"
+	lexer := lexer.NewLexer(input)
+	parser := NewParser(lexer)
+	contains, err := parser.ParseContains()
+
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	snaps.MatchSnapshot(t, contains.String())
+}
+
 func Test_Parser_ParseExpression(t *testing.T) {
 	testCases := map[string]struct {
 		input string
@@ -82,6 +366,174 @@ func Test_Parser_ParseExpression(t *testing.T) {
 		"Integer": {
 			input: "123",
 		},
+		"String": {
+			input: `"hello world"`,
+		},
+		"Label": {
+			input: "order",
+		},
+		"Path": {
+			input: "order.customer.email",
+		},
+		"Index": {
+			input: "items[0]",
+		},
+		"Negative index": {
+			input: "items[-1]",
+		},
+		"Index path": {
+			input: "order.items[0].sku",
+		},
+		"Slice": {
+			input: "items[1:3]",
+		},
+		"Slice open start": {
+			input: "items[:3]",
+		},
+		"Slice open end": {
+			input: "items[1:]",
+		},
+		"Slice open": {
+			input: "items[:]",
+		},
+		"Wildcard": {
+			input: "items[*].sku",
+		},
+		"Recursive": {
+			input: "order..id",
+		},
+		"Recursive context": {
+			input: "..id",
+		},
+		"Filter": {
+			input: "items[price equals 10].sku",
+		},
+		"Filter nested": {
+			input: "orders[items[0].sku equals 1]",
+		},
+		"Add": {
+			input: "a + b - c",
+		},
+		"Multiply": {
+			input: "a * b / c % d",
+		},
+		"Arithmetic precedence": {
+			input: "a + b * c - d / e",
+		},
+		"Arithmetic group": {
+			input: "(total - discount) / 2",
+		},
+		"Negate": {
+			input: "-price * --2",
+		},
+		"Subtract negative": {
+			input: "2 - -1",
+		},
+		"Index arithmetic": {
+			input: "items[n - 1]",
+		},
+		"Quoted label": {
+			input: `person."first name"`,
+		},
+		"Quoted keyword": {
+			input: `filter.'equals'.value`,
+		},
+		"Context": {
+			input: ".",
+		},
+		"Context index": {
+			input: ".[0]",
+		},
+		"Context filter": {
+			input: `tags[. equals "beta"]`,
+		},
+		"Path filter": {
+			input: "items[active].id",
+		},
+		"Field path filter": {
+			input: "items[.flags.active]",
+		},
+		"Keyword label": {
+			input: "filter.equals",
+		},
+		"Keyword label spaced": {
+			input: "order. contains",
+		},
+		"Keyword recursive": {
+			input: "..not",
+		},
+		"Keyword context": {
+			input: ".in",
+		},
+		"Quoted context": {
+			input: `."content-type"`,
+		},
+		"Quoted recursive": {
+			input: `..'not'`,
+		},
+		"String index": {
+			input: `headers["content-type"]`,
+		},
+		"True": {
+			input: "true",
+		},
+		"False": {
+			input: "false",
+		},
+		"Null": {
+			input: "null",
+		},
+		"List": {
+			input: `["open", "pending", 1 + 2]`,
+		},
+		"List empty": {
+			input: "[]",
+		},
+		"List nested": {
+			input: "[[1], [2, [3]]]",
+		},
+		"List index": {
+			input: "[1, 2, 3][-1]",
+		},
+		"Object": {
+			input: `{"b": 1, a: [true, null], 'c d': {}}`,
+		},
+		"Object empty": {
+			input: "{}",
+		},
+		"Object field": {
+			input: "{a: 1}.a",
+		},
+		"Call": {
+			input: "length(items)",
+		},
+		"Call arguments": {
+			input: "round(price * 1.1, 2)",
+		},
+		"Call optional": {
+			input: "round(price)",
+		},
+		"Call nested": {
+			input: `join(split(path, "/"), ",")`,
+		},
+		"Call path": {
+			input: `split(path, "/")[1]`,
+		},
+		"Call case": {
+			input: "LOWER(name)",
+		},
+		"Call spaced": {
+			input: "trim (name)",
+		},
+		"Call wildcard arithmetic": {
+			input: "sum(items[*].price * 2)",
+		},
+		"Call wildcard negate": {
+			input: "sum(-items[*].price)",
+		},
+		"Call extract": {
+			input: "length(message extract `user=(\\w+)`)",
+		},
 	}
 
 	for name, tc := range testCases {
@@ -104,8 +556,107 @@ func Test_Parser_ParseExpression_Error(t *testing.T) {
 		input string
 	}{
 		"Unknown": {
+			input: ")",
+		},
+		"Unbalanced": {
 			input: "(",
 		},
+		"Unbalanced operation": {
+			input: "(a equals 1 equals",
+		},
+		"Path EOF": {
+			input: "order.",
+		},
+		"Path number": {
+			input: "order.1",
+		},
+		"Index unclosed": {
+			input: "items[0",
+		},
+		"Index extra": {
+			input: "items[0 1]",
+		},
+		"Slice unclosed": {
+			input: "items[0:1",
+		},
+		"Wildcard unclosed": {
+			input: "items[*",
+		},
+		"Recursive number": {
+			input: "..1",
+		},
+		"Filter slice": {
+			input: "items[price equals 10:2]",
+		},
+		"Add EOF": {
+			input: "a +",
+		},
+		"Multiply operation": {
+			input: "a * equals",
+		},
+		"Negate EOF": {
+			input: "-",
+		},
+		"Call unknown": {
+			input: "shout(name)",
+		},
+		"Call too few": {
+			input: `split(path)`,
+		},
+		"Call too many": {
+			input: "abs(1, 2)",
+		},
+		"Call optional too many": {
+			input: "round(1, 2, 3)",
+		},
+		"Call no arguments": {
+			input: "lower()",
+		},
+		"Call argument type": {
+			input: `abs("1")`,
+		},
+		"Call argument arithmetic": {
+			input: "length(1 + 2)",
+		},
+		"Call argument literal arithmetic": {
+			input: "sum(2 * -3)",
+		},
+		"Call argument condition": {
+			input: "length(a equals 1)",
+		},
+		"Call argument nested": {
+			input: "abs(lower(name))",
+		},
+		"Call unclosed": {
+			input: "lower(name",
+		},
+		"Call missing comma": {
+			input: `split(path "/")`,
+		},
+		"List unclosed": {
+			input: "[1, 2",
+		},
+		"List missing comma": {
+			input: "[1 2]",
+		},
+		"List trailing comma": {
+			input: "[1,]",
+		},
+		"Object number key": {
+			input: "{1: 2}",
+		},
+		"Object missing colon": {
+			input: `{"a" 1}`,
+		},
+		"Object missing comma": {
+			input: `{"a": 1 "b": 2}`,
+		},
+		"Object duplicate key": {
+			input: `{a: 1, "a": 2}`,
+		},
+		"Object unclosed": {
+			input: `{"a": 1`,
+		},
 	}
 
 	for name, tc := range testCases {
@@ -133,6 +684,30 @@ func Test_parseNumber(t *testing.T) {
 		"Float": {
 			value: "123.456",
 		},
+		"Negative": {
+			value: "-5",
+		},
+		"Positive": {
+			value: "+5",
+		},
+		"Exponent": {
+			value: "1.5e3",
+		},
+		"Negative exponent": {
+			value: "-25E-2",
+		},
+		"Separators": {
+			value: "1_000_000",
+		},
+		"Hexadecimal": {
+			value: "0xFF",
+		},
+		"Negative hexadecimal": {
+			value: "-0x1_0",
+		},
+		"Binary": {
+			value: "0B1010",
+		},
 	}
 
 	for name, tc := range testCases {
@@ -161,6 +736,18 @@ func Test_parseNumber_Error(t *testing.T) {
 			typ:   lexer.TokenType_Number,
 			value: "kachow",
 		},
+		"Bad hexadecimal": {
+			typ:   lexer.TokenType_Number,
+			value: "0xZZ",
+		},
+		"Exponent too large": {
+			typ:   lexer.TokenType_Number,
+			value: "1e999999999",
+		},
+		"Exponent too small": {
+			typ:   lexer.TokenType_Number,
+			value: "1e-999999999",
+		},
 		"Wrong type": {
 			typ: lexer.TokenType_OpenParan,
 		},
@@ -168,7 +755,7 @@ func Test_parseNumber_Error(t *testing.T) {
 
 	for name, tc := range testCases {
 		t.Run(name, func(t *testing.T) {
-			_, err := parseNumber(lexer.Token{Type: tc.typ, Value: tc.value})
+			_, err := parseNumber(lexer.Token{Type: tc.typ, Value: tc.value, Position: 4})
 
 			if err == nil {
 				t.Fatalf("Expected error but none returned")
@@ -178,3 +765,140 @@ func Test_parseNumber_Error(t *testing.T) {
 		})
 	}
 }
+
+func Test_Parser_ParseQuery_UnbalancedParentheses(t *testing.T) {
+	testCases := map[string]struct {
+		input string
+	}{
+		"Open": {
+			input: "(a equals 1",
+		},
+		"Close": {
+			input: "a equals 1)",
+		},
+		"Open only": {
+			input: "(",
+		},
+		"Open at end": {
+			input: "a equals (",
+		},
+		"Nested open": {
+			input: "((",
+		},
+		"Call open": {
+			input: "lower(",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			lexer := lexer.NewLexer(tc.input)
+			parser := NewParser(lexer)
+			_, err := parser.ParseQuery()
+
+			if !errors.Is(err, UnbalancedParentheses) {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func Test_Parser_ParseQuery_SymbolicAliases(t *testing.T) {
+	testCases := map[string]struct {
+		keyword  string
+		symbolic string
+	}{
+		"Equals": {
+			keyword:  "status equals 1",
+			symbolic: "status = 1",
+		},
+		"Double equals": {
+			keyword:  "status equals 1",
+			symbolic: "status == 1",
+		},
+		"Not equals": {
+			keyword:  "status not equals 1",
+			symbolic: "status != 1",
+		},
+		"Contains": {
+			keyword:  `tags contains "beta"`,
+			symbolic: `tags ~ "beta"`,
+		},
+		"Not contains": {
+			keyword:  `tags not contains "beta"`,
+			symbolic: `tags !~ "beta"`,
+		},
+		"Greater": {
+			keyword:  "age greater 18",
+			symbolic: "age > 18",
+		},
+		"Lesser": {
+			keyword:  "age lesser 18",
+			symbolic: "age < 18",
+		},
+		"Not": {
+			keyword:  "not (a equals 1)",
+			symbolic: "!(a = 1)",
+		},
+		"Greater than": {
+			keyword:  "age greater than 18",
+			symbolic: "age > 18",
+		},
+		"Less than": {
+			keyword:  "age less than 18",
+			symbolic: "age < 18",
+		},
+		"Greater or equals": {
+			keyword:  "age greater or equals 18",
+			symbolic: "age >= 18",
+		},
+		"At least": {
+			keyword:  "age at least 18",
+			symbolic: "age >= 18",
+		},
+		"At most": {
+			keyword:  "age at most 65",
+			symbolic: "age <= 65",
+		},
+		"Less than or equal to": {
+			keyword:  "age less than or equal to 65",
+			symbolic: "age <= 65",
+		},
+		"Lesser than or equal to": {
+			keyword:  "age lesser than or equal to 65",
+			symbolic: "age <= 65",
+		},
+		"Greater or equal to": {
+			keyword:  "age greater or equal to 18",
+			symbolic: "age >= 18",
+		},
+		"Less or equals": {
+			keyword:  "age less or equals 65",
+			symbolic: "age <= 65",
+		},
+		"Not equal to": {
+			keyword:  "status not equal to 1",
+			symbolic: "status != 1",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			keyword, err := NewParser(lexer.NewLexer(tc.keyword)).ParseQuery()
+
+			if err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+
+			symbolic, err := NewParser(lexer.NewLexer(tc.symbolic)).ParseQuery()
+
+			if err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+
+			if keyword.String() != symbolic.String() {
+				t.Fatalf("Unexpected AST\nExpected: %s\nActual: %s", keyword.String(), symbolic.String())
+			}
+		})
+	}
+}